@@ -0,0 +1,147 @@
+package progressbar
+
+import (
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+// savedState is the state written by MarshalState
+type savedState struct {
+	Max           int64
+	CurrentNum    int64
+	CurrentBytes  float64
+	StartingBytes float64
+	// Elapsed is the number of seconds the bar has been running, excluding
+	// the time spent paused
+	Elapsed float64
+	// Rates are the rate samples, and RateAges how many seconds before the
+	// state was saved each of them was taken
+	Rates    []float64
+	RateAges []float64
+	Finished bool
+}
+
+// checkpoint holds the settings of OptionCheckpointFile
+type checkpoint struct {
+	path     string
+	interval time.Duration
+	lastSave time.Time
+}
+
+// MarshalState returns the state of the bar as JSON, to restore it with
+// NewFromState after the program restarts. It holds the progress, the
+// elapsed time and the rate samples, so the restored bar continues with
+// the same ETA.
+func (p *ProgressBar) MarshalState() ([]byte, error) {
+	p.lock.Lock()
+	defer p.lock.Unlock()
+
+	return p.marshalState()
+}
+
+// marshalState returns the state of the bar as JSON. this function is not
+// thread-safe, so it must be called with an acquired lock.
+func (p *ProgressBar) marshalState() ([]byte, error) {
+	now := time.Now()
+	s := savedState{
+		Max:           p.config.max,
+		CurrentNum:    p.state.currentNum,
+		CurrentBytes:  p.state.currentBytes,
+		StartingBytes: p.state.startingBytes,
+		Rates:         p.state.counterLastTenRates,
+		RateAges:      make([]float64, len(p.state.counterRateTimes)),
+		Finished:      p.state.finished,
+	}
+	if p.config.ignoreLength {
+		s.Max = -1
+	}
+	if p.IsStarted() {
+		s.Elapsed = p.state.elapsed().Seconds()
+	}
+	for i, t := range p.state.counterRateTimes {
+		s.RateAges[i] = now.Sub(t).Seconds()
+	}
+	return json.Marshal(s)
+}
+
+// NewFromState constructs a new instance of ProgressBar from the state
+// returned by MarshalState, with any options you specify. The options of the
+// original bar are not saved, so pass them again.
+func NewFromState(data []byte, options ...Option) (*ProgressBar, error) {
+	var s savedState
+	if err := json.Unmarshal(data, &s); err != nil {
+		return nil, err
+	}
+	if len(s.Rates) != len(s.RateAges) {
+		return nil, errors.New("invalid state: rates and rate ages do not match")
+	}
+	return NewOptions64(s.Max, withOptions(options, optionRestoreState(s))...), nil
+}
+
+// optionRestoreState restores the saved state. It is always applied last, so
+// options such as OptionSetStartingBytes do not override it.
+func optionRestoreState(s savedState) Option {
+	return func(p *ProgressBar) {
+		now := time.Now()
+		p.state.currentNum = s.CurrentNum
+		p.state.currentBytes = s.CurrentBytes
+		p.state.startingBytes = s.StartingBytes
+		p.state.finished = s.Finished
+		if s.Elapsed > 0 {
+			p.state.startTime = now.Add(-time.Duration(s.Elapsed * float64(time.Second)))
+			p.state.counterTime = now
+		}
+		p.state.counterLastTenRates = append([]float64(nil), s.Rates...)
+		p.state.counterRateTimes = make([]time.Time, len(s.RateAges))
+		for i, age := range s.RateAges {
+			p.state.counterRateTimes[i] = now.Add(-time.Duration(age * float64(time.Second)))
+		}
+	}
+}
+
+// OptionCheckpointFile writes the state of the bar to path at most once per
+// interval while it renders, and once more when it finishes or exits, to
+// restore it with NewFromState after a crash or a restart. The file is
+// replaced atomically, so it always holds a complete state.
+func OptionCheckpointFile(path string, interval time.Duration) Option {
+	return func(p *ProgressBar) {
+		p.config.checkpoint = &checkpoint{path: path, interval: interval}
+	}
+}
+
+// saveCheckpoint writes the state to the checkpoint file if the interval has
+// passed, or always if force is set. this function is not thread-safe, so it
+// must be called with an acquired lock.
+func (p *ProgressBar) saveCheckpoint(force bool) error {
+	c := p.config.checkpoint
+	if c == nil || (!force && time.Since(c.lastSave) < c.interval) {
+		return nil
+	}
+	data, err := p.marshalState()
+	if err != nil {
+		return err
+	}
+	c.lastSave = time.Now()
+
+	f, err := os.CreateTemp(filepath.Dir(c.path), filepath.Base(c.path)+".*.tmp")
+	if err != nil {
+		return err
+	}
+	if _, err := f.Write(data); err != nil {
+		f.Close()
+		os.Remove(f.Name())
+		return err
+	}
+	if err := f.Close(); err != nil {
+		os.Remove(f.Name())
+		return err
+	}
+	if err := os.Rename(f.Name(), c.path); err != nil {
+		os.Remove(f.Name())
+		return err
+	}
+	return nil
+}
@@ -0,0 +1,81 @@
+package progressbar
+
+import (
+	"io"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMarshalState(t *testing.T) {
+	bar := NewOptions(1000, OptionSetWriter(io.Discard), OptionSetStartingBytes(100))
+	bar.Add(200)
+	bar.lock.Lock()
+	bar.state.startTime = bar.state.startTime.Add(-time.Minute)
+	bar.state.counterLastTenRates = []float64{10, 20}
+	bar.state.counterRateTimes = []time.Time{time.Now().Add(-2 * time.Second), time.Now().Add(-time.Second)}
+	bar.lock.Unlock()
+
+	data, err := bar.MarshalState()
+	assert.NoError(t, err)
+
+	restored, err := NewFromState(data, OptionSetWriter(io.Discard), OptionSetStartingBytes(5))
+	assert.NoError(t, err)
+	state := restored.State()
+	assert.Equal(t, int64(1000), state.Max)
+	assert.Equal(t, int64(300), state.CurrentNum)
+	assert.Equal(t, 300.0, state.CurrentBytes)
+	assert.Equal(t, 100.0, restored.state.startingBytes)
+	assert.InDelta(t, 60, state.SecondsSince, 1)
+	assert.Equal(t, []float64{10, 20}, restored.state.counterLastTenRates)
+	assert.WithinDuration(t, time.Now().Add(-2*time.Second), restored.state.counterRateTimes[0], 100*time.Millisecond)
+	assert.Equal(t, 15.0, averageRate(restored.config, &restored.state))
+
+	restored.Add(700)
+	assert.True(t, restored.IsFinished())
+}
+
+func TestNewFromStateInvalid(t *testing.T) {
+	_, err := NewFromState([]byte("{"))
+	assert.Error(t, err)
+	_, err = NewFromState([]byte(`{"Max":10,"Rates":[1]}`))
+	assert.Error(t, err)
+}
+
+func TestNewFromStateUnknownLength(t *testing.T) {
+	bar := NewOptions(-1, OptionSetWriter(io.Discard))
+	bar.Add(5)
+	data, _ := bar.MarshalState()
+
+	restored, err := NewFromState(data, OptionSetWriter(io.Discard))
+	assert.NoError(t, err)
+	assert.Equal(t, int64(-1), restored.State().Max)
+	assert.Equal(t, int64(5), restored.State().CurrentNum)
+}
+
+func TestOptionCheckpointFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "bar.json")
+	bar := NewOptions(10, OptionSetWriter(io.Discard), OptionCheckpointFile(path, time.Hour))
+	bar.Add(2)
+	bar.Add(3)
+
+	// the second render falls within the interval
+	data, err := os.ReadFile(path)
+	assert.NoError(t, err)
+	restored, err := NewFromState(data, OptionSetWriter(io.Discard))
+	assert.NoError(t, err)
+	assert.Equal(t, int64(2), restored.State().CurrentNum)
+
+	bar.Exit()
+	data, err = os.ReadFile(path)
+	assert.NoError(t, err)
+	restored, err = NewFromState(data, OptionSetWriter(io.Discard))
+	assert.NoError(t, err)
+	assert.Equal(t, int64(5), restored.State().CurrentNum)
+
+	entries, _ := os.ReadDir(filepath.Dir(path))
+	assert.Len(t, entries, 1, "expected no temporary files left behind")
+}
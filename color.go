@@ -0,0 +1,174 @@
+package progressbar
+
+import (
+	"fmt"
+	"image/color"
+	"os"
+	"sort"
+	"strings"
+)
+
+// ColorStop is the color of the saucer at a percentage, see
+// OptionColorByPercent
+type ColorStop struct {
+	Percent int
+	Color   color.Color
+}
+
+// colorDepth is the number of colors supported by the terminal
+type colorDepth int
+
+const (
+	colorDepth16 colorDepth = iota
+	colorDepth256
+	colorDepthTrue
+)
+
+// detectColorDepth guesses the colors supported by the terminal from the
+// environment
+func detectColorDepth() colorDepth {
+	switch strings.ToLower(os.Getenv("COLORTERM")) {
+	case "truecolor", "24bit":
+		return colorDepthTrue
+	}
+	if os.Getenv("WT_SESSION") != "" {
+		// Windows Terminal supports truecolor, without setting COLORTERM
+		return colorDepthTrue
+	}
+	if strings.Contains(os.Getenv("TERM"), "256color") {
+		return colorDepth256
+	}
+	return colorDepth16
+}
+
+// saucerColor returns the color of a cell of the saucer, given the width of
+// the bar and the current percentage
+type saucerColor func(cell, width, percent int) color.Color
+
+// OptionSaucerGradient colors each cell of the saucer along a gradient from
+// the color from, at the start of the bar, to the color to, at its end.
+//
+// Like OptionColorByPercent, it requires OptionEnableColorCodes, otherwise
+// the bar is not colored. It uses 24-bit colors if the terminal supports
+// them according to COLORTERM, otherwise the closest of the 256 or 16 colors
+// the terminal supports.
+func OptionSaucerGradient(from, to color.Color) Option {
+	stops := []ColorStop{{0, from}, {100, to}}
+	return func(p *ProgressBar) {
+		p.config.saucerColor = func(cell, width, _ int) color.Color {
+			if width <= 1 {
+				return interpolateColor(stops, 0)
+			}
+			return interpolateColor(stops, float64(cell)*100/float64(width-1))
+		}
+		p.config.colorDepth = detectColorDepth()
+	}
+}
+
+// OptionColorByPercent colors the whole saucer according to the current
+// percentage, blending the colors of the stops around it. For example,
+// red at 0, yellow at 50 and green at 100 shifts from red to green as the
+// bar fills.
+//
+// Like OptionSaucerGradient, it requires OptionEnableColorCodes, and it
+// replaces OptionSaucerGradient if both are set.
+func OptionColorByPercent(stops []ColorStop) Option {
+	stops = append([]ColorStop(nil), stops...)
+	sort.SliceStable(stops, func(i, j int) bool { return stops[i].Percent < stops[j].Percent })
+	return func(p *ProgressBar) {
+		if len(stops) == 0 {
+			p.config.saucerColor = nil
+			return
+		}
+		p.config.saucerColor = func(_, _, percent int) color.Color {
+			return interpolateColor(stops, float64(percent))
+		}
+		p.config.colorDepth = detectColorDepth()
+	}
+}
+
+// interpolateColor blends the colors of the stops around percent, which
+// must be sorted
+func interpolateColor(stops []ColorStop, percent float64) color.Color {
+	if percent <= float64(stops[0].Percent) {
+		return stops[0].Color
+	}
+	for i := 1; i < len(stops); i++ {
+		if percent <= float64(stops[i].Percent) {
+			a, b := stops[i-1], stops[i]
+			t := (percent - float64(a.Percent)) / float64(b.Percent-a.Percent)
+			return blend(a.Color, b.Color, t)
+		}
+	}
+	return stops[len(stops)-1].Color
+}
+
+// blend returns the color at t, between 0 and 1, from a to b
+func blend(a, b color.Color, t float64) color.Color {
+	ar, ag, ab := rgb(a)
+	br, bg, bb := rgb(b)
+	mix := func(x, y uint8) uint8 {
+		return uint8(float64(x) + (float64(y)-float64(x))*t + 0.5)
+	}
+	return color.RGBA{mix(ar, br), mix(ag, bg), mix(ab, bb), 0xff}
+}
+
+// rgb returns the 8-bit components of c
+func rgb(c color.Color) (r, g, b uint8) {
+	r32, g32, b32, _ := c.RGBA()
+	return uint8(r32 >> 8), uint8(g32 >> 8), uint8(b32 >> 8)
+}
+
+// ansi16 are the colors of the 16-color palette, as in xterm, indexed by
+// their offset from 30 for the normal colors and from 90 for the bright ones
+var ansi16 = [16][3]uint8{
+	{0, 0, 0}, {205, 0, 0}, {0, 205, 0}, {205, 205, 0},
+	{0, 0, 238}, {205, 0, 205}, {0, 205, 205}, {229, 229, 229},
+	{127, 127, 127}, {255, 0, 0}, {0, 255, 0}, {255, 255, 0},
+	{92, 92, 255}, {255, 0, 255}, {0, 255, 255}, {255, 255, 255},
+}
+
+// foregroundColor returns the escape code that sets the foreground to c, or
+// to the closest color supported by depth
+func foregroundColor(c color.Color, depth colorDepth) string {
+	r, g, b := rgb(c)
+	switch depth {
+	case colorDepthTrue:
+		return fmt.Sprintf("\033[38;2;%d;%d;%dm", r, g, b)
+	case colorDepth256:
+		// the 6x6x6 color cube starts at 16
+		cube := func(x uint8) int { return (int(x)*5 + 127) / 255 }
+		return fmt.Sprintf("\033[38;5;%dm", 16+36*cube(r)+6*cube(g)+cube(b))
+	}
+	best, bestDist := 0, -1
+	for i, p := range ansi16 {
+		dr, dg, db := int(r)-int(p[0]), int(g)-int(p[1]), int(b)-int(p[2])
+		if dist := dr*dr + dg*dg + db*db; bestDist < 0 || dist < bestDist {
+			best, bestDist = i, dist
+		}
+	}
+	if best < 8 {
+		return fmt.Sprintf("\033[%dm", 30+best)
+	}
+	return fmt.Sprintf("\033[%dm", 90+best-8)
+}
+
+// colorSaucer returns the cells of the saucer, each colored by c.saucerColor.
+// The escape code is only repeated when the color changes.
+func colorSaucer(c config, s *state, cells []string) string {
+	var b strings.Builder
+	last := ""
+	for i, cell := range cells {
+		code := foregroundColor(c.saucerColor(i, c.width, s.currentPercent), c.colorDepth)
+		if code != last {
+			b.WriteString(code)
+			last = code
+		}
+		b.WriteString(cell)
+	}
+	if last != "" {
+		// reset the foreground only, to keep the colors of the theme
+		b.WriteString("\033[39m")
+	}
+	return b.String()
+}
@@ -0,0 +1,67 @@
+package progressbar
+
+import (
+	"image/color"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+var (
+	red    = color.RGBA{255, 0, 0, 255}
+	yellow = color.RGBA{255, 255, 0, 255}
+	green  = color.RGBA{0, 255, 0, 255}
+)
+
+func TestOptionSaucerGradient(t *testing.T) {
+	t.Setenv("COLORTERM", "truecolor")
+	bar := NewOptions(100,
+		OptionSetWriter(io.Discard), OptionSetPredictTime(false),
+		OptionSetWidth(5),
+		OptionEnableColorCodes(true),
+		OptionSaucerGradient(red, green),
+	)
+	bar.Add(60)
+	assert.Equal(t, "\r  60% |"+
+		"\033[38;2;255;0;0m█\033[38;2;191;64;0m█\033[38;2;128;128;0m█\033[39m  |  ",
+		bar.String())
+	assert.Equal(t, 15, getStringWidth(bar.config, bar.String()))
+}
+
+func TestOptionColorByPercent(t *testing.T) {
+	t.Setenv("COLORTERM", "truecolor")
+	bar := NewOptions(100,
+		OptionSetWriter(io.Discard), OptionSetPredictTime(false),
+		OptionSetWidth(4),
+		OptionEnableColorCodes(true),
+		OptionColorByPercent([]ColorStop{{100, green}, {0, red}, {50, yellow}}),
+	)
+	bar.Add(50)
+	assert.Equal(t, "\r  50% |\033[38;2;255;255;0m██\033[39m  |  ", bar.String())
+	bar.Add(25)
+	assert.Equal(t, "\r  75% |\033[38;2;128;255;0m███\033[39m |  ", bar.String())
+}
+
+func TestSaucerColorDepth(t *testing.T) {
+	t.Setenv("COLORTERM", "")
+	t.Setenv("WT_SESSION", "")
+	t.Setenv("TERM", "xterm-256color")
+	bar := NewOptions(100, OptionSetWriter(io.Discard), OptionSetPredictTime(false), OptionSetWidth(2),
+		OptionEnableColorCodes(true), OptionColorByPercent([]ColorStop{{0, color.RGBA{255, 128, 0, 255}}}))
+	bar.Add(50)
+	assert.Equal(t, "\r  50% |\033[38;5;214m█\033[39m |  ", bar.String())
+
+	t.Setenv("TERM", "xterm")
+	bar = NewOptions(100, OptionSetWriter(io.Discard), OptionSetPredictTime(false), OptionSetWidth(2),
+		OptionEnableColorCodes(true), OptionColorByPercent([]ColorStop{{0, color.RGBA{250, 10, 10, 255}}}))
+	bar.Add(50)
+	assert.Equal(t, "\r  50% |\033[91m█\033[39m |  ", bar.String())
+}
+
+func TestSaucerColorWithoutColorCodes(t *testing.T) {
+	bar := NewOptions(100, OptionSetWriter(io.Discard), OptionSetPredictTime(false), OptionSetWidth(2), OptionSaucerGradient(red, green))
+	bar.Add(50)
+	assert.False(t, strings.Contains(bar.String(), "\033"), "expected no color without OptionEnableColorCodes")
+}
@@ -0,0 +1,88 @@
+package progressbar
+
+import (
+	"io"
+	"net/http"
+	"os"
+)
+
+// Copy copies from src to dst like io.Copy, showing a bar with the same
+// defaults as DefaultBytes, which the options can override. The size of src
+// is inferred if src is an *os.File, an *io.LimitedReader, an io.Seeker or
+// has a Len method like *bytes.Reader, otherwise the bar is a spinner.
+//
+// The bar is finished once src is copied, or exited if the copy fails. If
+// src is known to be empty, the bar is left as is. Only the errors of the
+// copy are returned, not those of the bar.
+func Copy(dst io.Writer, src io.Reader, options ...Option) (int64, error) {
+	return copyWithSize(dst, src, sizeOf(src), options)
+}
+
+// CopyResponse copies the body of resp to dst like Copy, using
+// resp.ContentLength as the size. It does not close the body.
+func CopyResponse(dst io.Writer, resp *http.Response, options ...Option) (int64, error) {
+	return copyWithSize(dst, resp.Body, resp.ContentLength, options)
+}
+
+// CopyRequest copies the body of req to dst like Copy, using
+// req.ContentLength as the size. It does not close the body.
+func CopyRequest(dst io.Writer, req *http.Request, options ...Option) (int64, error) {
+	return copyWithSize(dst, req.Body, req.ContentLength, options)
+}
+
+func copyWithSize(dst io.Writer, src io.Reader, size int64, options []Option) (int64, error) {
+	if size < 0 {
+		size = -1
+	}
+	bar := NewOptions64(size, append(defaultBytesOptions(), options...)...)
+	reader := NewReader(src, bar)
+	n, err := io.Copy(dst, &reader)
+	if err != nil {
+		bar.Exit()
+		return n, err
+	}
+	if size != 0 {
+		// a bar with a max of 0 cannot be finished, and there was nothing to
+		// show. The copy succeeded, so the bar failing to render is ignored.
+		bar.Finish()
+	}
+	return n, nil
+}
+
+// sizeOf returns the number of bytes left to read from r, or -1 if unknown
+func sizeOf(r io.Reader) int64 {
+	switch v := r.(type) {
+	case *os.File:
+		// only regular files have a meaningful size
+		fi, err := v.Stat()
+		if err != nil || !fi.Mode().IsRegular() {
+			return -1
+		}
+		offset, err := v.Seek(0, io.SeekCurrent)
+		if err != nil {
+			return -1
+		}
+		return fi.Size() - offset
+	case *io.LimitedReader:
+		if size := sizeOf(v.R); size >= 0 && size < v.N {
+			return size
+		}
+		return v.N
+	case interface{ Len() int }:
+		return int64(v.Len())
+	case io.Seeker:
+		offset, err := v.Seek(0, io.SeekCurrent)
+		if err != nil {
+			return -1
+		}
+		end, err := v.Seek(0, io.SeekEnd)
+		if err != nil {
+			return -1
+		}
+		if _, err := v.Seek(offset, io.SeekStart); err != nil {
+			return -1
+		}
+		return end - offset
+	}
+	return -1
+}
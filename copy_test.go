@@ -0,0 +1,194 @@
+package progressbar
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSizeOf(t *testing.T) {
+	f, err := os.CreateTemp(t.TempDir(), "src")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+	f.WriteString("hello world")
+	f.Seek(6, io.SeekStart)
+
+	tests := []struct {
+		name     string
+		reader   io.Reader
+		expected int64
+	}{
+		{"file", f, 5},
+		{"limited", io.LimitReader(strings.NewReader("hello world"), 5), 5},
+		{"limited beyond size", io.LimitReader(strings.NewReader("hello"), 100), 5},
+		{"len", bytes.NewBufferString("hello"), 5},
+		{"seeker", struct{ io.ReadSeeker }{strings.NewReader("hello")}, 5},
+		{"unknown", struct{ io.Reader }{strings.NewReader("hello")}, -1},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			assert.Equal(t, test.expected, sizeOf(test.reader))
+		})
+	}
+
+	// the offset of a seeker is left untouched
+	r := strings.NewReader("hello")
+	r.Seek(2, io.SeekStart)
+	assert.Equal(t, int64(3), sizeOf(struct{ io.ReadSeeker }{r}))
+	offset, _ := r.Seek(0, io.SeekCurrent)
+	assert.Equal(t, int64(2), offset)
+}
+
+func TestCopyNewlineOnCompletion(t *testing.T) {
+	buf := strings.Builder{}
+	_, err := Copy(io.Discard, strings.NewReader("hello"), OptionSetWriter(&buf))
+	assert.NoError(t, err)
+	assert.True(t, strings.HasSuffix(buf.String(), "\n"))
+	assert.False(t, strings.HasSuffix(buf.String(), "\n\n"), "expected a single newline in line mode, got %q", buf.String())
+
+	oldIsTerminal := isTerminal
+	isTerminal = func(w io.Writer) bool {
+		return true
+	}
+	defer func() {
+		isTerminal = oldIsTerminal
+	}()
+	buf.Reset()
+	_, err = Copy(io.Discard, strings.NewReader("hello"), OptionSetWriter(&buf))
+	assert.NoError(t, err)
+	assert.True(t, strings.HasSuffix(buf.String(), ")\n"), "expected the bar to end with a newline, got %q", buf.String())
+}
+
+func TestCopy(t *testing.T) {
+	var frames []Frame
+	record := OptionRenderer(RendererFunc(func(w io.Writer, f Frame) error {
+		frames = append(frames, f)
+		return nil
+	}))
+
+	dst := strings.Builder{}
+	n, err := Copy(&dst, strings.NewReader("hello"), record, OptionOnCompletion(nil))
+	assert.NoError(t, err)
+	assert.Equal(t, int64(5), n)
+	assert.Equal(t, "hello", dst.String())
+	assert.Equal(t, int64(5), frames[len(frames)-1].Max)
+	assert.True(t, frames[len(frames)-1].Finished)
+
+	frames = nil
+	n, err = Copy(io.Discard, struct{ io.Reader }{strings.NewReader("hello")}, record, OptionOnCompletion(nil))
+	assert.NoError(t, err)
+	assert.Equal(t, int64(5), n)
+	assert.Equal(t, int64(-1), frames[0].Max)
+	assert.True(t, frames[len(frames)-1].Finished)
+}
+
+type failingReader struct{}
+
+func (failingReader) Read(p []byte) (int, error) {
+	return 0, errors.New("failed")
+}
+
+func TestCopyError(t *testing.T) {
+	var last Frame
+	_, err := Copy(io.Discard, failingReader{}, OptionOnCompletion(nil), OptionRenderer(RendererFunc(func(w io.Writer, f Frame) error {
+		last = f
+		return nil
+	})))
+	assert.Error(t, err)
+	assert.True(t, last.Exited)
+}
+
+func TestCopyRenderError(t *testing.T) {
+	n, err := Copy(io.Discard, strings.NewReader("hello"), OptionOnCompletion(nil), OptionRenderer(RendererFunc(func(w io.Writer, f Frame) error {
+		return errors.New("failed")
+	})))
+	assert.NoError(t, err)
+	assert.Equal(t, int64(5), n)
+}
+
+func TestCopySpinnerCustom(t *testing.T) {
+	var last Frame
+	n, err := Copy(io.Discard, struct{ io.Reader }{strings.NewReader("hello")}, OptionSpinnerCustom([]string{"*"}), OptionOnCompletion(nil), OptionRenderer(RendererFunc(func(w io.Writer, f Frame) error {
+		last = f
+		return nil
+	})))
+	assert.NoError(t, err)
+	assert.Equal(t, int64(5), n)
+	assert.True(t, last.Finished, "expected the custom spinner to be combined with the defaults")
+}
+
+func TestCopyResponse(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		io.WriteString(w, "hello world")
+	}))
+	defer server.Close()
+
+	resp, err := http.Get(server.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer resp.Body.Close()
+
+	var last Frame
+	dst := strings.Builder{}
+	n, err := CopyResponse(&dst, resp, OptionOnCompletion(nil), OptionRenderer(RendererFunc(func(w io.Writer, f Frame) error {
+		last = f
+		return nil
+	})))
+	assert.NoError(t, err)
+	assert.Equal(t, int64(11), n)
+	assert.Equal(t, "hello world", dst.String())
+	assert.Equal(t, int64(11), last.Max)
+}
+
+func TestCopyEmpty(t *testing.T) {
+	n, err := Copy(io.Discard, strings.NewReader(""), OptionSetWriter(io.Discard))
+	assert.NoError(t, err)
+	assert.Equal(t, int64(0), n)
+
+	f, err := os.Create(filepath.Join(t.TempDir(), "empty"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+	n, err = Copy(io.Discard, f, OptionSetWriter(io.Discard))
+	assert.NoError(t, err)
+	assert.Equal(t, int64(0), n)
+}
+
+func TestCopyResponseEmpty(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Length", "0")
+	}))
+	defer server.Close()
+
+	resp, err := http.Get(server.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer resp.Body.Close()
+	assert.Equal(t, int64(0), resp.ContentLength)
+
+	n, err := CopyResponse(io.Discard, resp, OptionSetWriter(io.Discard))
+	assert.NoError(t, err)
+	assert.Equal(t, int64(0), n)
+}
+
+func TestCopyRequest(t *testing.T) {
+	req := httptest.NewRequest(http.MethodPost, "/", strings.NewReader("hello"))
+	dst := strings.Builder{}
+	n, err := CopyRequest(&dst, req, OptionOnCompletion(nil), OptionSetVisibility(false))
+	assert.NoError(t, err)
+	assert.Equal(t, int64(5), n)
+	assert.Equal(t, "hello", dst.String())
+}
@@ -0,0 +1,138 @@
+package progressbar
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"net/http"
+	"os"
+	"time"
+)
+
+// Download downloads url to destPath with client, or http.DefaultClient if
+// client is nil, showing a bar with the same
+// defaults as DefaultBytes, which the options can override.
+//
+// If destPath already holds part of the file, for example from an
+// interrupted download, only the rest is requested with a Range header, and
+// the bar starts from the bytes already present (see OptionSetStartingBytes).
+// The modification time of destPath is set to the Last-Modified time of the
+// remote file, and the range is requested with an If-Range header holding
+// it, so a server that honors it sends the whole file again if it has
+// changed since. If the server ignores the range or answers with an
+// unexpected Content-Range, the download starts over.
+//
+// destPath is only created once the server has answered successfully.
+func Download(ctx context.Context, client *http.Client, url, destPath string, options ...Option) error {
+	var offset int64
+	var modTime time.Time
+	fi, err := os.Stat(destPath)
+	switch {
+	case err == nil:
+		offset, modTime = fi.Size(), fi.ModTime()
+	case !errors.Is(err, fs.ErrNotExist):
+		return err
+	}
+
+	resp, err := downloadRequest(ctx, client, url, offset, modTime)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if offset > 0 {
+		restart := false
+		switch resp.StatusCode {
+		case http.StatusPartialContent:
+			var start, end, total int64
+			n, _ := fmt.Sscanf(resp.Header.Get("Content-Range"), "bytes %d-%d/%d", &start, &end, &total)
+			switch {
+			case n < 2 || start != offset:
+				// the server sent another part than the one requested
+				restart = true
+			case n == 3:
+				resp.ContentLength = total
+			case resp.ContentLength >= 0:
+				// the total size is unknown to the server
+				resp.ContentLength += offset
+			}
+		case http.StatusRequestedRangeNotSatisfiable:
+			var total int64
+			if n, _ := fmt.Sscanf(resp.Header.Get("Content-Range"), "bytes */%d", &total); n == 1 && total == offset {
+				// the file is already complete
+				return nil
+			}
+			restart = true
+		default:
+			// the server ignores ranges, or the file has changed, and it
+			// sends the whole file
+			offset = 0
+		}
+		if restart {
+			resp.Body.Close()
+			if resp, err = downloadRequest(ctx, client, url, 0, time.Time{}); err != nil {
+				return err
+			}
+			defer resp.Body.Close()
+			offset = 0
+		}
+	}
+	if resp.StatusCode != http.StatusOK && resp.StatusCode != http.StatusPartialContent {
+		return fmt.Errorf("download %s: unexpected status %s", url, resp.Status)
+	}
+
+	f, err := os.OpenFile(destPath, os.O_WRONLY|os.O_CREATE, 0644)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	if offset == 0 {
+		if err := f.Truncate(0); err != nil {
+			return err
+		}
+	}
+	if _, err := f.Seek(offset, io.SeekStart); err != nil {
+		return err
+	}
+
+	if resp.ContentLength >= 0 {
+		// a spinner cannot start from the bytes already present
+		options = withOptions(options, OptionSetStartingBytes(offset))
+	}
+	n, err := copyWithSize(f, resp.Body, resp.ContentLength, options)
+	if err != nil && offset == 0 && n == 0 {
+		// there is nothing to resume from
+		f.Close()
+		os.Remove(destPath)
+		return err
+	}
+	if lastModified, perr := http.ParseTime(resp.Header.Get("Last-Modified")); perr == nil {
+		// the validator of the partial file, for If-Range when resuming
+		if cerr := os.Chtimes(destPath, lastModified, lastModified); err == nil {
+			err = cerr
+		}
+	}
+	return err
+}
+
+// downloadRequest requests url, from offset onwards if offset is not zero,
+// unless it has been modified since modTime
+func downloadRequest(ctx context.Context, client *http.Client, url string, offset int64, modTime time.Time) (*http.Response, error) {
+	if client == nil {
+		client = http.DefaultClient
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return nil, err
+	}
+	if offset > 0 {
+		req.Header.Set("Range", fmt.Sprintf("bytes=%d-", offset))
+		if !modTime.IsZero() {
+			req.Header.Set("If-Range", modTime.UTC().Format(http.TimeFormat))
+		}
+	}
+	return client.Do(req)
+}
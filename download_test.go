@@ -0,0 +1,164 @@
+package progressbar
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+var downloadContent = strings.Repeat("0123456789", 100)
+
+// downloadModTime is the modification time of the remote file, older than
+// any partial file
+var downloadModTime = time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)
+
+func downloadServer(t *testing.T, ignoreRange bool, requests *[]string) *httptest.Server {
+	t.Helper()
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		*requests = append(*requests, r.Header.Get("Range"))
+		if ignoreRange {
+			io.WriteString(w, downloadContent)
+			return
+		}
+		http.ServeContent(w, r, "file", downloadModTime, strings.NewReader(downloadContent))
+	}))
+	t.Cleanup(server.Close)
+	return server
+}
+
+func downloadOptions(last *Frame) []Option {
+	return []Option{
+		OptionOnCompletion(nil),
+		OptionRenderer(RendererFunc(func(w io.Writer, f Frame) error {
+			*last = f
+			return nil
+		})),
+	}
+}
+
+func TestDownload(t *testing.T) {
+	var requests []string
+	server := downloadServer(t, false, &requests)
+	dest := filepath.Join(t.TempDir(), "file")
+
+	var last Frame
+	err := Download(context.Background(), nil, server.URL, dest, downloadOptions(&last)...)
+	assert.NoError(t, err)
+	got, _ := os.ReadFile(dest)
+	assert.Equal(t, downloadContent, string(got))
+	assert.Equal(t, []string{""}, requests)
+	assert.True(t, last.Finished)
+	assert.Equal(t, int64(len(downloadContent)), last.Max)
+	fi, _ := os.Stat(dest)
+	assert.True(t, downloadModTime.Equal(fi.ModTime()), "expected the modification time of the remote file, got %v", fi.ModTime())
+}
+
+func TestDownloadResume(t *testing.T) {
+	var requests []string
+	server := downloadServer(t, false, &requests)
+	dest := filepath.Join(t.TempDir(), "file")
+	os.WriteFile(dest, []byte(downloadContent[:400]), 0644)
+	os.Chtimes(dest, downloadModTime, downloadModTime)
+
+	var last Frame
+	var bar *ProgressBar
+	options := append(downloadOptions(&last), func(p *ProgressBar) { bar = p })
+	err := Download(context.Background(), server.Client(), server.URL, dest, options...)
+	assert.NoError(t, err)
+	got, _ := os.ReadFile(dest)
+	assert.Equal(t, downloadContent, string(got))
+	assert.Equal(t, []string{"bytes=400-"}, requests)
+	assert.Equal(t, int64(len(downloadContent)), last.Max)
+	assert.Equal(t, 400.0, bar.state.startingBytes)
+}
+
+func TestDownloadChanged(t *testing.T) {
+	// the remote file has changed since the partial file was written
+	var ranges []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		ranges = append(ranges, r.Header.Get("Range"))
+		http.ServeContent(w, r, "file", time.Now().Add(time.Hour), strings.NewReader(downloadContent))
+	}))
+	defer server.Close()
+	dest := filepath.Join(t.TempDir(), "file")
+	os.WriteFile(dest, []byte("stale"), 0644)
+
+	err := Download(context.Background(), nil, server.URL, dest, OptionSetVisibility(false))
+	assert.NoError(t, err)
+	got, _ := os.ReadFile(dest)
+	assert.Equal(t, downloadContent, string(got))
+	assert.Equal(t, []string{"bytes=5-"}, ranges)
+}
+
+func TestDownloadComplete(t *testing.T) {
+	var requests []string
+	server := downloadServer(t, false, &requests)
+	dest := filepath.Join(t.TempDir(), "file")
+	os.WriteFile(dest, []byte(downloadContent), 0644)
+
+	err := Download(context.Background(), nil, server.URL, dest, OptionSetVisibility(false))
+	assert.NoError(t, err)
+	got, _ := os.ReadFile(dest)
+	assert.Equal(t, downloadContent, string(got))
+	assert.Equal(t, []string{"bytes=1000-"}, requests)
+}
+
+func TestDownloadRangeIgnored(t *testing.T) {
+	var requests []string
+	server := downloadServer(t, true, &requests)
+	dest := filepath.Join(t.TempDir(), "file")
+	os.WriteFile(dest, []byte("garbage"), 0644)
+
+	err := Download(context.Background(), nil, server.URL, dest, OptionSetVisibility(false))
+	assert.NoError(t, err)
+	got, _ := os.ReadFile(dest)
+	assert.Equal(t, downloadContent, string(got))
+}
+
+func TestDownloadLarger(t *testing.T) {
+	// a partial file larger than the remote file cannot be resumed
+	var requests []string
+	server := downloadServer(t, false, &requests)
+	dest := filepath.Join(t.TempDir(), "file")
+	os.WriteFile(dest, bytes.Repeat([]byte("x"), 2000), 0644)
+	os.Chtimes(dest, downloadModTime, downloadModTime)
+
+	err := Download(context.Background(), nil, server.URL, dest, OptionSetVisibility(false))
+	assert.NoError(t, err)
+	got, _ := os.ReadFile(dest)
+	assert.Equal(t, downloadContent, string(got))
+	assert.Equal(t, []string{"bytes=2000-", ""}, requests)
+}
+
+func TestDownloadStatus(t *testing.T) {
+	server := httptest.NewServer(http.NotFoundHandler())
+	defer server.Close()
+
+	dest := filepath.Join(t.TempDir(), "file")
+	err := Download(context.Background(), nil, server.URL, dest, OptionSetVisibility(false))
+	assert.ErrorContains(t, err, "404")
+	_, err = os.Stat(dest)
+	assert.ErrorIs(t, err, os.ErrNotExist, "expected no file to be left behind")
+}
+
+func TestDownloadCanceled(t *testing.T) {
+	var requests []string
+	server := downloadServer(t, false, &requests)
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	dest := filepath.Join(t.TempDir(), "file")
+	err := Download(ctx, nil, server.URL, dest, OptionSetVisibility(false))
+	assert.ErrorIs(t, err, context.Canceled)
+	_, err = os.Stat(dest)
+	assert.ErrorIs(t, err, os.ErrNotExist, "expected no file to be left behind")
+}
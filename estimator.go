@@ -0,0 +1,223 @@
+package progressbar
+
+import (
+	"math"
+	"time"
+)
+
+// Estimator estimates the rate of progress of a bar, and thus the time left.
+// Set it with OptionEstimator.
+type Estimator interface {
+	// Observe records that the bar reached n at time t. If n is lower than
+	// the previous observation, for example after Reset, the estimator
+	// starts over.
+	Observe(t time.Time, n int64)
+	// Rate returns the estimated rate, in units per second, or 0 if unknown
+	Rate() float64
+	// Remaining returns the estimated time to progress n more units,
+	// or 0 if unknown
+	Remaining(n int64) time.Duration
+}
+
+// OptionEstimator sets the Estimator used for the rate and the predicted time
+// left. By default, the bar samples the rate like NewSampleEstimator.
+func OptionEstimator(e Estimator) Option {
+	return func(p *ProgressBar) {
+		p.config.estimator = e
+	}
+}
+
+// remaining returns the time to progress n units at rate, or 0 if unknown
+func remaining(rate float64, n int64) time.Duration {
+	if rate <= 0 || math.IsInf(rate, 0) || math.IsNaN(rate) {
+		return 0
+	}
+	return time.Duration(float64(n) / rate * float64(time.Second))
+}
+
+// rateSampleInterval is the minimum time between two samples of the rate
+const rateSampleInterval = 500 * time.Millisecond
+
+// sampleRate takes a sample of the rate if more than rateSampleInterval has
+// passed since the last sample, at last, with num units of progress since
+// then. It returns the samples, trimmed to the window, and whether a sample
+// was taken. Both the bar and sampleEstimator sample the rate with it.
+func sampleRate(rates []float64, times []time.Time, window time.Duration, last time.Time, num int64, now time.Time) ([]float64, []time.Time, bool) {
+	dt := now.Sub(last)
+	if dt <= rateSampleInterval {
+		return rates, times, false
+	}
+	rates = append(rates, float64(num)/dt.Seconds())
+	times = append(times, now)
+	rates, times = trimRateSamples(rates, times, window, now)
+	return rates, times, true
+}
+
+// sampleEstimator samples the rate at most every half a second and averages
+// the samples, like the bar does by default
+type sampleEstimator struct {
+	window time.Duration
+
+	started    bool
+	sampleTime time.Time
+	sampleNum  int64
+	lastNum    int64
+
+	rates []float64
+	times []time.Time
+}
+
+// NewSampleEstimator returns the Estimator the bar uses by default. It
+// samples the rate at most every half a second, and averages the last few
+// samples, or every sample within the trailing window if window is greater
+// than zero (see OptionSetRateAveragingWindow). Until the first sample, the
+// rate is unknown, and the bar shows the total rate so far instead.
+func NewSampleEstimator(window time.Duration) Estimator {
+	return &sampleEstimator{window: window}
+}
+
+func (e *sampleEstimator) Observe(t time.Time, n int64) {
+	if !e.started || n < e.lastNum {
+		*e = sampleEstimator{
+			window:     e.window,
+			started:    true,
+			sampleTime: t,
+			sampleNum:  n,
+			lastNum:    n,
+		}
+		return
+	}
+	e.lastNum = n
+	var sampled bool
+	e.rates, e.times, sampled = sampleRate(e.rates, e.times, e.window, e.sampleTime, n-e.sampleNum, t)
+	if sampled {
+		e.sampleTime = t
+		e.sampleNum = n
+	}
+}
+
+func (e *sampleEstimator) Rate() float64 {
+	if len(e.rates) == 0 {
+		return 0
+	}
+	return average(e.rates)
+}
+
+func (e *sampleEstimator) Remaining(n int64) time.Duration {
+	return remaining(e.Rate(), n)
+}
+
+// ewmaEstimator keeps an exponentially weighted moving average of the rate
+type ewmaEstimator struct {
+	halfLife time.Duration
+
+	started  bool
+	lastTime time.Time
+	lastNum  int64
+	rate     float64
+	hasRate  bool
+}
+
+// NewEWMAEstimator returns an Estimator that keeps an exponentially weighted
+// moving average of the rate. The weight of an observation is halved every
+// halfLife, regardless of how often the bar is updated, which smooths out
+// bursty workloads.
+func NewEWMAEstimator(halfLife time.Duration) Estimator {
+	return &ewmaEstimator{halfLife: halfLife}
+}
+
+func (e *ewmaEstimator) Observe(t time.Time, n int64) {
+	if !e.started || n < e.lastNum {
+		*e = ewmaEstimator{halfLife: e.halfLife, started: true, lastTime: t, lastNum: n}
+		return
+	}
+	dt := t.Sub(e.lastTime).Seconds()
+	if dt <= 0 {
+		// the progress is accounted for at the next observation
+		return
+	}
+	rate := float64(n-e.lastNum) / dt
+	if !e.hasRate || e.halfLife <= 0 {
+		e.rate = rate
+		e.hasRate = true
+	} else {
+		weight := 1 - math.Exp2(-dt/e.halfLife.Seconds())
+		e.rate += weight * (rate - e.rate)
+	}
+	e.lastTime = t
+	e.lastNum = n
+}
+
+func (e *ewmaEstimator) Rate() float64 {
+	return e.rate
+}
+
+func (e *ewmaEstimator) Remaining(n int64) time.Duration {
+	return remaining(e.Rate(), n)
+}
+
+// regressionBucket is the minimum time between two observations kept by the
+// regression estimator, so a bar updated in a tight loop does not keep
+// thousands of them
+const regressionBucket = 100 * time.Millisecond
+
+// regressionEstimator fits a line through the observations in a trailing window
+type regressionEstimator struct {
+	window time.Duration
+
+	times []time.Time
+	nums  []int64
+}
+
+// NewRegressionEstimator returns an Estimator whose rate is the slope of the
+// least-squares line through the observations within the trailing window.
+// Observations closer than a tenth of a second are merged, keeping the latest.
+func NewRegressionEstimator(window time.Duration) Estimator {
+	return &regressionEstimator{window: window}
+}
+
+func (e *regressionEstimator) Observe(t time.Time, n int64) {
+	if len(e.nums) > 0 && n < e.nums[len(e.nums)-1] {
+		e.times, e.nums = nil, nil
+	}
+	if last := len(e.times) - 1; last > 0 && t.Sub(e.times[last-1]) < regressionBucket {
+		// the last observation is too close to the previous one, replace it
+		e.times[last], e.nums[last] = t, n
+	} else {
+		e.times = append(e.times, t)
+		e.nums = append(e.nums, n)
+	}
+
+	// keep at least two observations to fit a line through
+	cutoff := t.Add(-e.window)
+	drop := 0
+	for drop < len(e.times)-2 && e.times[drop].Before(cutoff) {
+		drop++
+	}
+	e.times, e.nums = e.times[drop:], e.nums[drop:]
+}
+
+func (e *regressionEstimator) Rate() float64 {
+	if len(e.times) < 2 {
+		return 0
+	}
+	var sumX, sumY, sumXY, sumXX float64
+	for i, t := range e.times {
+		x := t.Sub(e.times[0]).Seconds()
+		y := float64(e.nums[i])
+		sumX += x
+		sumY += y
+		sumXY += x * y
+		sumXX += x * x
+	}
+	count := float64(len(e.times))
+	denominator := count*sumXX - sumX*sumX
+	if denominator == 0 {
+		return 0
+	}
+	return (count*sumXY - sumX*sumY) / denominator
+}
+
+func (e *regressionEstimator) Remaining(n int64) time.Duration {
+	return remaining(e.Rate(), n)
+}
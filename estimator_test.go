@@ -0,0 +1,137 @@
+package progressbar
+
+import (
+	"io"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSampleEstimator(t *testing.T) {
+	base := time.Now()
+	e := NewSampleEstimator(0)
+	assert.Equal(t, time.Duration(0), e.Remaining(10))
+
+	e.Observe(base, 0)
+	e.Observe(base.Add(time.Second), 10)
+	assert.Equal(t, 10.0, e.Rate())
+	// too soon after the last sample to take another one
+	e.Observe(base.Add(1200*time.Millisecond), 12)
+	assert.Equal(t, 10.0, e.Rate())
+	assert.Equal(t, 2*time.Second, e.Remaining(20))
+
+	e.Observe(base.Add(2*time.Second), 30)
+	assert.InDelta(t, (10.0+20.0)/2, e.Rate(), 0.001)
+
+	// going backwards starts over
+	e.Observe(base.Add(3*time.Second), 0)
+	e.Observe(base.Add(4*time.Second), 50)
+	assert.Equal(t, 50.0, e.Rate())
+
+	// without samples, the rate is unknown
+	e = NewSampleEstimator(0)
+	e.Observe(base, 0)
+	e.Observe(base.Add(200*time.Millisecond), 10)
+	assert.Equal(t, 0.0, e.Rate())
+}
+
+func TestSampleEstimatorMatchesDefault(t *testing.T) {
+	bar := NewOptions(1000, OptionSetWriter(io.Discard))
+	estimated := NewOptions(1000, OptionSetWriter(io.Discard), OptionEstimator(NewSampleEstimator(0)))
+	bar.Add(100)
+	estimated.Add(100)
+	for i := 0; i < 2; i++ {
+		time.Sleep(600 * time.Millisecond)
+		bar.Add(100)
+		estimated.Add(100)
+		bar.lock.Lock()
+		estimated.lock.Lock()
+		assert.InEpsilon(t, averageRate(bar.config, &bar.state), averageRate(estimated.config, &estimated.state), 0.05)
+		estimated.lock.Unlock()
+		bar.lock.Unlock()
+	}
+}
+
+func TestEWMAEstimator(t *testing.T) {
+	base := time.Now()
+	e := NewEWMAEstimator(time.Second)
+	e.Observe(base, 0)
+	assert.Equal(t, 0.0, e.Rate())
+	e.Observe(base.Add(time.Second), 10)
+	assert.Equal(t, 10.0, e.Rate())
+	// after one half-life, the new rate weighs as much as the average
+	e.Observe(base.Add(2*time.Second), 30)
+	assert.InDelta(t, 15.0, e.Rate(), 0.001)
+	assert.Equal(t, 2*time.Second, e.Remaining(30))
+}
+
+func TestRegressionEstimator(t *testing.T) {
+	base := time.Now()
+	e := NewRegressionEstimator(time.Minute)
+	e.Observe(base, 0)
+	assert.Equal(t, 0.0, e.Rate())
+
+	// a burst of progress is spread over the window
+	e.Observe(base.Add(1*time.Second), 0)
+	e.Observe(base.Add(2*time.Second), 30)
+	e.Observe(base.Add(3*time.Second), 30)
+	assert.InDelta(t, 12.0, e.Rate(), 0.001)
+
+	// observations outside the window are dropped
+	e = NewRegressionEstimator(2 * time.Second)
+	e.Observe(base, 0)
+	e.Observe(base.Add(10*time.Second), 0)
+	e.Observe(base.Add(11*time.Second), 10)
+	assert.InDelta(t, 10.0, e.Rate(), 0.001)
+
+	// frequent observations are merged, keeping the latest
+	e = NewRegressionEstimator(time.Minute)
+	for i := 0; i <= 10000; i++ {
+		e.Observe(base.Add(time.Duration(i)*time.Millisecond), int64(i))
+	}
+	nums := e.(*regressionEstimator).nums
+	assert.LessOrEqual(t, len(nums), 110)
+	assert.Equal(t, int64(10000), nums[len(nums)-1])
+	assert.InDelta(t, 1000.0, e.Rate(), 0.001)
+}
+
+// fixedEstimator is an Estimator with a fixed rate
+type fixedEstimator struct {
+	observed []int64
+}
+
+func (e *fixedEstimator) Observe(t time.Time, n int64) {
+	e.observed = append(e.observed, n)
+}
+
+func (e *fixedEstimator) Rate() float64 {
+	return 5
+}
+
+func (e *fixedEstimator) Remaining(n int64) time.Duration {
+	return time.Duration(n) * time.Second / 5
+}
+
+func TestOptionEstimator(t *testing.T) {
+	e := &fixedEstimator{}
+	var last Frame
+	bar := NewOptions(100,
+		OptionSetWriter(io.Discard),
+		OptionSetWidth(10),
+		OptionSetStartingBytes(10),
+		OptionEstimator(e),
+	)
+	bar.Add(10)
+	assert.True(t, strings.HasSuffix(bar.String(), "[0s:16s]"), "got %q", bar.String())
+	assert.Equal(t, []int64{0, 10}, e.observed)
+
+	bar.config.renderer = RendererFunc(func(w io.Writer, f Frame) error {
+		last = f
+		return nil
+	})
+	bar.Add(10)
+	assert.Equal(t, 5.0, last.Rate)
+	assert.Equal(t, 14*time.Second, last.ETA)
+}
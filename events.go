@@ -0,0 +1,153 @@
+package progressbar
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sync"
+	"time"
+)
+
+// sseKeepAlive is how often a comment is sent on an idle /events stream,
+// so proxies do not close it
+var sseKeepAlive = 15 * time.Second
+
+// sseEvent is a message of the /events stream
+type sseEvent struct {
+	// name is the event type, or empty for the state sent on every render
+	name string
+	data []byte
+}
+
+// eventHub hands the state of a bar to the /events subscribers. It is locked
+// after the bar, never before.
+type eventHub struct {
+	lock sync.Mutex
+	subs map[chan sseEvent]struct{}
+	// final is the finished or exit event, once sent
+	final *sseEvent
+}
+
+func newEventHub() *eventHub {
+	return &eventHub{subs: make(map[chan sseEvent]struct{})}
+}
+
+// subscribe returns a channel receiving the events, and a function to stop
+// receiving them. If the bar is already done, the final event is pending.
+func (h *eventHub) subscribe() (chan sseEvent, func()) {
+	h.lock.Lock()
+	defer h.lock.Unlock()
+
+	ch := make(chan sseEvent, 1)
+	if h.final != nil {
+		ch <- *h.final
+	} else {
+		h.subs[ch] = struct{}{}
+	}
+	return ch, func() {
+		h.lock.Lock()
+		delete(h.subs, ch)
+		h.lock.Unlock()
+	}
+}
+
+// publish sends e to every subscriber without blocking. A subscriber that is
+// behind only receives the latest event, so a slow client never slows down
+// the bar.
+func (h *eventHub) publish(e sseEvent, final bool) {
+	h.lock.Lock()
+	defer h.lock.Unlock()
+
+	if h.final != nil {
+		return
+	}
+	for ch := range h.subs {
+		select {
+		case <-ch:
+		default:
+		}
+		ch <- e
+	}
+	if final {
+		h.final = &e
+		h.subs = nil
+	}
+}
+
+// publishEvent sends the current state to the /events subscribers, if any.
+// name is "finished" or "exit" for the final event, or empty otherwise.
+// this function is not thread-safe, so it must be called with an acquired
+// lock.
+func (p *ProgressBar) publishEvent(name string) {
+	h := p.config.events
+	if h == nil {
+		return
+	}
+	data, err := json.Marshal(finiteState(p.currentState()))
+	if err != nil {
+		return
+	}
+	h.publish(sseEvent{name: name, data: data}, name != "")
+}
+
+// serveEvents streams the state of the bar as Server-Sent Events: an unnamed
+// event with the JSON State on every render, then a final "finished" or
+// "exit" event.
+func (p *ProgressBar) serveEvents(w http.ResponseWriter, r *http.Request) {
+	flusher, ok := w.(http.Flusher)
+	if !ok {
+		http.Error(w, "streaming unsupported", http.StatusInternalServerError)
+		return
+	}
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.Header().Set("Connection", "keep-alive")
+
+	p.lock.Lock()
+	ch, unsubscribe := p.config.events.subscribe()
+	// start with the current state, unless the final event is pending
+	var data []byte
+	if len(ch) == 0 {
+		data, _ = json.Marshal(finiteState(p.currentState()))
+	}
+	p.lock.Unlock()
+	defer unsubscribe()
+
+	if data != nil {
+		writeSSE(w, sseEvent{data: data})
+	}
+	flusher.Flush()
+
+	ticker := time.NewTicker(sseKeepAlive)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-r.Context().Done():
+			return
+		case e := <-ch:
+			if err := writeSSE(w, e); err != nil {
+				return
+			}
+			flusher.Flush()
+			if e.name != "" {
+				return
+			}
+		case <-ticker.C:
+			if _, err := fmt.Fprint(w, ": keep-alive\n\n"); err != nil {
+				return
+			}
+			flusher.Flush()
+		}
+	}
+}
+
+// writeSSE writes e in the text/event-stream format
+func writeSSE(w http.ResponseWriter, e sseEvent) error {
+	if e.name != "" {
+		if _, err := fmt.Fprintf(w, "event: %s\n", e.name); err != nil {
+			return err
+		}
+	}
+	_, err := fmt.Fprintf(w, "data: %s\n\n", e.data)
+	return err
+}
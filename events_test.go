@@ -0,0 +1,116 @@
+package progressbar
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// readSSE reads the next event of the stream, skipping comments
+func readSSE(t *testing.T, r *bufio.Reader) (name string, state State) {
+	t.Helper()
+
+	for {
+		line, err := r.ReadString('\n')
+		if err != nil {
+			t.Fatalf("read event: %v", err)
+		}
+		line = strings.TrimSuffix(line, "\n")
+		switch {
+		case strings.HasPrefix(line, "event: "):
+			name = strings.TrimPrefix(line, "event: ")
+		case strings.HasPrefix(line, "data: "):
+			if err := json.Unmarshal([]byte(strings.TrimPrefix(line, "data: ")), &state); err != nil {
+				t.Fatalf("decode state: %v", err)
+			}
+		case line == "" && state.Max != 0:
+			return name, state
+		}
+	}
+}
+
+func TestStartHTTPServerEvents(t *testing.T) {
+	bar := NewOptions(10, OptionSetWriter(io.Discard))
+	hostPort := freeTestHTTPAddr(t)
+	svr := bar.StartHTTPServer(hostPort)
+	defer svr.Close()
+
+	var clients []*bufio.Reader
+	for i := 0; i < 2; i++ {
+		resp := getHTTPWithRetry(t, fmt.Sprintf("http://%s/events", hostPort))
+		defer resp.Body.Close()
+		assert.Equal(t, "text/event-stream", resp.Header.Get("Content-Type"))
+		clients = append(clients, bufio.NewReader(resp.Body))
+	}
+
+	for _, c := range clients {
+		name, state := readSSE(t, c)
+		assert.Equal(t, "", name)
+		assert.Equal(t, int64(0), state.CurrentNum)
+	}
+
+	bar.Add(3)
+	for _, c := range clients {
+		name, state := readSSE(t, c)
+		assert.Equal(t, "", name)
+		assert.Equal(t, int64(3), state.CurrentNum)
+	}
+
+	bar.Finish()
+	for _, c := range clients {
+		name, state := readSSE(t, c)
+		assert.Equal(t, "finished", name)
+		assert.Equal(t, int64(10), state.CurrentNum)
+		_, err := c.ReadString('\n')
+		assert.ErrorIs(t, err, io.EOF, "expected the stream to end")
+	}
+
+	// a late subscriber only receives the final event
+	resp := getHTTPWithRetry(t, fmt.Sprintf("http://%s/events", hostPort))
+	defer resp.Body.Close()
+	name, _ := readSSE(t, bufio.NewReader(resp.Body))
+	assert.Equal(t, "finished", name)
+}
+
+func TestStartHTTPServerEventsExit(t *testing.T) {
+	defer func(d time.Duration) { sseKeepAlive = d }(sseKeepAlive)
+	sseKeepAlive = 10 * time.Millisecond
+
+	bar := NewOptions(10, OptionSetWriter(io.Discard))
+	bar.Add(1)
+	hostPort := freeTestHTTPAddr(t)
+	svr := bar.StartHTTPServer(hostPort)
+	defer svr.Close()
+
+	resp := getHTTPWithRetry(t, fmt.Sprintf("http://%s/events", hostPort))
+	defer resp.Body.Close()
+	r := bufio.NewReader(resp.Body)
+	readSSE(t, r)
+
+	line, err := r.ReadString('\n')
+	assert.NoError(t, err)
+	assert.Equal(t, ": keep-alive\n", line)
+
+	bar.Exit()
+	name, state := readSSE(t, r)
+	assert.Equal(t, "exit", name)
+	assert.Equal(t, int64(1), state.CurrentNum)
+}
+
+func TestEventHubSlowSubscriber(t *testing.T) {
+	h := newEventHub()
+	ch, unsubscribe := h.subscribe()
+	defer unsubscribe()
+
+	// publishing never blocks, the subscriber only gets the latest event
+	for i := 0; i < 100; i++ {
+		h.publish(sseEvent{data: []byte(fmt.Sprint(i))}, false)
+	}
+	assert.Equal(t, "99", string((<-ch).data))
+}
@@ -14,7 +14,7 @@ func main() {
 		progressbar.OptionSetWidth(10),
 		progressbar.OptionThrottle(65*time.Millisecond),
 		progressbar.OptionShowCount(),
-		progressbar.OptionSpinnerType(14),
+		progressbar.OptionSpinner(progressbar.SpinnerDots),
 		progressbar.OptionFullWidth(),
 		progressbar.OptionSetRenderBlankState(true),
 	)
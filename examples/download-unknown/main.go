@@ -1,7 +1,6 @@
 package main
 
 import (
-	"io"
 	"net/http"
 	"os"
 
@@ -10,15 +9,15 @@ import (
 
 func main() {
 	req, _ := http.NewRequest("GET", "https://dl.google.com/go/go1.14.2.src.tar.gz", nil)
-	resp, _ := http.DefaultClient.Do(req)
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return
+	}
 	defer resp.Body.Close()
 
 	f, _ := os.OpenFile("go1.14.2.src.tar.gz", os.O_CREATE|os.O_WRONLY, 0644)
 	defer f.Close()
 
-	bar := progressbar.DefaultBytes(
-		-1,
-		"downloading",
-	)
-	io.Copy(io.MultiWriter(f, bar), resp.Body)
+	// Copy cannot tell the size of the body, so the bar is a spinner
+	progressbar.Copy(f, resp.Body, progressbar.OptionSetDescription("downloading"))
 }
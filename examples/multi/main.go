@@ -0,0 +1,33 @@
+package main
+
+import (
+	"fmt"
+	"math/rand"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/schollz/progressbar/v3"
+)
+
+// This example renders several downloads running side by side
+func main() {
+	mp := progressbar.NewMultiProgress(os.Stderr)
+
+	var wg sync.WaitGroup
+	for i := 0; i < 4; i++ {
+		bar := mp.New(100,
+			progressbar.OptionSetDescription(fmt.Sprintf("file %d", i+1)),
+			progressbar.OptionSetWidth(20),
+		)
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for j := 0; j < 100; j++ {
+				bar.Add(1)
+				time.Sleep(time.Duration(10+rand.Intn(40)) * time.Millisecond)
+			}
+		}()
+	}
+	wg.Wait()
+}
@@ -0,0 +1,82 @@
+package progressbar
+
+import (
+	"strings"
+)
+
+// IndeterminateStyle is the animation of a bar whose length is unknown
+type IndeterminateStyle int
+
+const (
+	// IndeterminateSpinner shows a spinner, see OptionSpinnerType. It is the default.
+	IndeterminateSpinner IndeterminateStyle = iota
+	// IndeterminateBounce slides a block back and forth inside the bar
+	IndeterminateBounce
+	// IndeterminateMarquee slides a block from the start to the end of the bar,
+	// wrapping around
+	IndeterminateMarquee
+	// IndeterminatePulse fades the whole bar in and out
+	IndeterminatePulse
+)
+
+// pulseShades are the frames of IndeterminatePulse
+var pulseShades = []string{"░", "▒", "▓", "█", "▓", "▒"}
+
+// OptionIndeterminateStyle sets the animation shown instead of the spinner
+// when the length is unknown. The bar is drawn at the width of the bar,
+// between the BarStart and BarEnd of the theme, with the Saucer of the theme
+// for the block and SaucerPadding around it. It moves every
+// OptionSetSpinnerChangeInterval, or on every render if the interval is 0.
+// With OptionTemplate, the animation is both the Bar and the Spinner.
+func OptionIndeterminateStyle(style IndeterminateStyle) Option {
+	return func(p *ProgressBar) {
+		p.config.indeterminateStyle = style
+	}
+}
+
+// renderIndeterminate returns the current frame of the animation of a bar
+// whose length is unknown
+func renderIndeterminate(c config, s *state) string {
+	var frame int
+	if c.spinnerChangeInterval != 0 {
+		frame = int(s.elapsed() / c.spinnerChangeInterval)
+	} else {
+		frame = s.animationFrame
+		s.animationFrame++
+	}
+
+	width := c.width
+	if width <= 0 {
+		return c.theme.BarStart + c.theme.BarEnd
+	}
+	block := max(1, width/4)
+	cells := make([]string, width)
+	for i := range cells {
+		cells[i] = c.theme.SaucerPadding
+	}
+
+	switch c.indeterminateStyle {
+	case IndeterminateBounce:
+		pos := 0
+		if travel := width - block; travel > 0 {
+			pos = frame % (2 * travel)
+			if pos > travel {
+				pos = 2*travel - pos
+			}
+		}
+		for i := pos; i < pos+block; i++ {
+			cells[i] = c.theme.Saucer
+		}
+	case IndeterminateMarquee:
+		pos := frame % width
+		for i := 0; i < block; i++ {
+			cells[(pos+i)%width] = c.theme.Saucer
+		}
+	case IndeterminatePulse:
+		shade := pulseShades[frame%len(pulseShades)]
+		for i := range cells {
+			cells[i] = shade
+		}
+	}
+	return c.theme.BarStart + strings.Join(cells, "") + c.theme.BarEnd
+}
@@ -0,0 +1,68 @@
+package progressbar
+
+import (
+	"io"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// indeterminateFrames renders n frames of the animation
+func indeterminateFrames(style IndeterminateStyle, width, n int) []string {
+	bar := NewOptions(-1,
+		OptionSetWriter(io.Discard),
+		OptionSetWidth(width),
+		OptionSetSpinnerChangeInterval(0),
+		OptionSetTheme(Theme{Saucer: "#", SaucerPadding: "-", BarStart: "[", BarEnd: "]"}),
+		OptionIndeterminateStyle(style),
+	)
+	var frames []string
+	for i := 0; i < n; i++ {
+		frames = append(frames, renderIndeterminate(bar.config, &bar.state))
+	}
+	return frames
+}
+
+func TestIndeterminateBounce(t *testing.T) {
+	assert.Equal(t, []string{
+		"[##------]",
+		"[-##-----]",
+		"[--##----]",
+		"[---##---]",
+		"[----##--]",
+		"[-----##-]",
+		"[------##]",
+		"[-----##-]",
+		"[----##--]",
+	}, indeterminateFrames(IndeterminateBounce, 8, 9))
+}
+
+func TestIndeterminateMarquee(t *testing.T) {
+	assert.Equal(t, []string{
+		"[#---]",
+		"[-#--]",
+		"[--#-]",
+		"[---#]",
+		"[#---]",
+	}, indeterminateFrames(IndeterminateMarquee, 4, 5))
+	assert.Equal(t, "[#------#]", indeterminateFrames(IndeterminateMarquee, 8, 8)[7])
+}
+
+func TestIndeterminatePulse(t *testing.T) {
+	assert.Equal(t, []string{"[░░░]", "[▒▒▒]", "[▓▓▓]", "[███]", "[▓▓▓]", "[▒▒▒]", "[░░░]"},
+		indeterminateFrames(IndeterminatePulse, 3, 7))
+}
+
+func TestOptionIndeterminateStyle(t *testing.T) {
+	bar := NewOptions(-1,
+		OptionSetWriter(io.Discard),
+		OptionSetWidth(4),
+		OptionSetSpinnerChangeInterval(0),
+		OptionSetDescription("waiting"),
+		OptionIndeterminateStyle(IndeterminateMarquee),
+	)
+	bar.Add(1)
+	assert.Equal(t, "\r|█   | waiting  [0s] ", bar.String())
+	bar.Add(1)
+	assert.Equal(t, "\r| █  | waiting  [0s] ", bar.String())
+}
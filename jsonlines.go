@@ -0,0 +1,107 @@
+package progressbar
+
+import (
+	"encoding/json"
+	"io"
+	"math"
+	"time"
+)
+
+// Event is a JSON line written by OptionJSONLines
+type Event struct {
+	// Event is one of "start", "progress", "detail", "finish" or "exit"
+	Event string
+	Time  time.Time
+
+	State
+
+	// Rate is the rolling average rate, in units per second
+	Rate float64
+	// ETA is the predicted number of seconds left, based on Rate
+	ETA      float64
+	Finished bool
+	Exited   bool
+	Paused   bool
+
+	// Detail is the detail added with AddDetail, only set for "detail" events
+	Detail string `json:",omitempty"`
+}
+
+// jsonRenderer writes every frame as a JSON line
+type jsonRenderer struct {
+	w       io.Writer
+	started bool
+}
+
+// OptionJSONLines writes the bar to w as JSON lines meant to be parsed by
+// other programs, instead of drawing it. Every render writes one Event: a
+// "start" event before the first one, "progress" events, and a final "finish"
+// or "exit" event. Each detail added with AddDetail is written as a "detail"
+// event.
+func OptionJSONLines(w io.Writer) Option {
+	return func(p *ProgressBar) {
+		p.config.renderer = &jsonRenderer{w: w}
+	}
+}
+
+func (r *jsonRenderer) Render(_ io.Writer, f Frame) error {
+	if !r.started {
+		r.started = true
+		if err := r.write(newEvent("start", f)); err != nil {
+			return err
+		}
+	}
+	switch {
+	case f.Exited:
+		return r.write(newEvent("exit", f))
+	case f.Finished:
+		return r.write(newEvent("finish", f))
+	}
+	return r.write(newEvent("progress", f))
+}
+
+func (r *jsonRenderer) RenderDetail(_ io.Writer, f Frame, detail string) error {
+	e := newEvent("detail", f)
+	e.Detail = detail
+	return r.write(e)
+}
+
+func (r *jsonRenderer) write(e Event) error {
+	bs, err := json.Marshal(e)
+	if err != nil {
+		return err
+	}
+	_, err = r.w.Write(append(bs, '\n'))
+	return err
+}
+
+func newEvent(event string, f Frame) Event {
+	e := Event{
+		Event:    event,
+		Time:     time.Now(),
+		State:    finiteState(f.State),
+		Rate:     finite(f.Rate),
+		ETA:      f.ETA.Seconds(),
+		Finished: f.Finished,
+		Exited:   f.Exited,
+		Paused:   f.Paused,
+	}
+	return e
+}
+
+// finiteState returns s with the fields that are not defined before the bar
+// starts set to 0, since JSON cannot encode NaN or infinite numbers
+func finiteState(s State) State {
+	s.CurrentPercent = finite(s.CurrentPercent)
+	s.SecondsLeft = finite(s.SecondsLeft)
+	s.KBsPerSecond = finite(s.KBsPerSecond)
+	return s
+}
+
+// finite returns x, or 0 if x is NaN or infinite
+func finite(x float64) float64 {
+	if math.IsNaN(x) || math.IsInf(x, 0) {
+		return 0
+	}
+	return x
+}
@@ -0,0 +1,69 @@
+package progressbar
+
+import (
+	"bufio"
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func readEvents(t *testing.T, s string) []Event {
+	t.Helper()
+	var events []Event
+	scanner := bufio.NewScanner(strings.NewReader(s))
+	for scanner.Scan() {
+		var e Event
+		if err := json.Unmarshal(scanner.Bytes(), &e); err != nil {
+			t.Fatalf("invalid JSON line %q: %v", scanner.Text(), err)
+		}
+		events = append(events, e)
+	}
+	return events
+}
+
+func TestOptionJSONLines(t *testing.T) {
+	terminal := strings.Builder{}
+	buf := strings.Builder{}
+	bar := NewOptions(10, OptionSetWriter(&terminal), OptionSetDescription("copying"), OptionJSONLines(&buf))
+	bar.Add(5)
+	bar.AddDetail("halfway")
+	bar.Add(5)
+
+	assert.Empty(t, terminal.String())
+	events := readEvents(t, buf.String())
+	var kinds []string
+	for _, e := range events {
+		kinds = append(kinds, e.Event)
+	}
+	assert.Equal(t, []string{"start", "progress", "detail", "finish"}, kinds)
+	assert.Equal(t, int64(5), events[1].CurrentNum)
+	assert.Equal(t, "copying", events[1].Description)
+	assert.Equal(t, "halfway", events[2].Detail)
+	assert.Equal(t, int64(5), events[2].CurrentNum)
+	assert.True(t, events[3].Finished)
+	assert.Equal(t, int64(10), events[3].Max)
+}
+
+func TestOptionJSONLinesExit(t *testing.T) {
+	buf := strings.Builder{}
+	bar := NewOptions(10, OptionJSONLines(&buf))
+	bar.Exit()
+
+	events := readEvents(t, buf.String())
+	assert.Len(t, events, 2)
+	assert.Equal(t, "exit", events[1].Event)
+	assert.True(t, events[1].Exited)
+}
+
+func TestOptionJSONLinesExitAfterFinish(t *testing.T) {
+	buf := strings.Builder{}
+	bar := NewOptions(10, OptionJSONLines(&buf))
+	bar.Finish()
+	bar.Exit()
+
+	events := readEvents(t, buf.String())
+	assert.Len(t, events, 2)
+	assert.Equal(t, "finish", events[1].Event)
+}
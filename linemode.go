@@ -0,0 +1,78 @@
+package progressbar
+
+import (
+	"errors"
+	"io"
+	"strings"
+	"time"
+)
+
+// lineRenderer prints the bar as plain, newline-terminated lines instead of
+// redrawing it in place, which suits log files and CI logs.
+type lineRenderer struct {
+	// percent is the progress between two lines, in percent
+	percent int
+	// interval is the time between two lines
+	interval time.Duration
+
+	printed     bool
+	lastPercent int
+	lastTime    time.Time
+}
+
+// OptionLineMode prints the bar as plain, newline-terminated lines instead of
+// redrawing it in place: one line every percent percent of progress or every
+// interval, whichever comes first, and a final line when the bar finishes or
+// exits. Passing zero for percent or interval disables that trigger.
+func OptionLineMode(percent int, interval time.Duration) Option {
+	return func(p *ProgressBar) {
+		p.config.renderer = &lineRenderer{percent: percent, interval: interval}
+	}
+}
+
+// OptionLineModeIfNotTerminal is like OptionLineMode, but only switches to
+// line mode if the writer is not a terminal, for example when the output is
+// redirected to a file. It has no effect if OptionRenderer is used.
+func OptionLineModeIfNotTerminal(percent int, interval time.Duration) Option {
+	return func(p *ProgressBar) {
+		p.config.lineModeFallback = &lineRenderer{percent: percent, interval: interval}
+	}
+}
+
+func (r *lineRenderer) Render(w io.Writer, f Frame) error {
+	if f.bar == nil {
+		return errors.New("line mode can only render frames of a ProgressBar")
+	}
+
+	now := time.Now()
+	percent := int(f.CurrentPercent * 100)
+	due := !r.printed || f.Finished || f.Exited
+	if r.percent > 0 && f.Max != -1 && percent >= r.lastPercent+r.percent {
+		due = true
+	}
+	if r.interval > 0 && now.Sub(r.lastTime) >= r.interval {
+		due = true
+	}
+	if !due {
+		return nil
+	}
+
+	r.printed = true
+	r.lastTime = now
+	if r.percent > 0 {
+		r.lastPercent = percent - percent%r.percent
+	}
+	_, err := io.WriteString(w, f.bar.renderLine()+"\n")
+	return err
+}
+
+// renderLine returns the bar rendered as a single line, without any control
+// characters. this function is not thread-safe, so it must be called with an
+// acquired lock.
+func (p *ProgressBar) renderLine() string {
+	c := p.config
+	c.writer = io.Discard
+	c.useANSICodes = false
+	renderProgressBar(c, &p.state)
+	return strings.TrimRight(strings.TrimPrefix(p.state.rendered, "\r"), " ")
+}
@@ -0,0 +1,89 @@
+package progressbar
+
+import (
+	"io"
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestOptionLineMode(t *testing.T) {
+	buf := strings.Builder{}
+	bar := NewOptions(100,
+		OptionSetWriter(&buf),
+		OptionSetWidth(10),
+		OptionSetPredictTime(false),
+		OptionLineMode(25, 0),
+	)
+	for i := 0; i < 100; i++ {
+		bar.Add(1)
+	}
+
+	assert.NotContains(t, buf.String(), "\r")
+	assert.Equal(t, ""+
+		"   1% |          |\n"+
+		"  25% |██        |\n"+
+		"  50% |█████     |\n"+
+		"  75% |███████   |\n"+
+		" 100% |██████████|\n", buf.String())
+}
+
+func TestOptionLineModeInterval(t *testing.T) {
+	buf := strings.Builder{}
+	bar := NewOptions(-1,
+		OptionSetWriter(&buf),
+		OptionSetSpinnerChangeInterval(0),
+		OptionSetElapsedTime(false),
+		OptionShowCount(),
+		OptionSpinnerCustom([]string{"-"}),
+		OptionLineMode(0, 50*time.Millisecond),
+	)
+	bar.Add(1)
+	bar.Add(1)
+	time.Sleep(60 * time.Millisecond)
+	bar.Add(1)
+	bar.Exit()
+
+	assert.Equal(t, "-  (1/-)\n-  (3/-)\n-  (3/-) aborted\n", buf.String())
+}
+
+func TestOptionLineModeExitAfterFinish(t *testing.T) {
+	buf := strings.Builder{}
+	bar := NewOptions(10,
+		OptionSetWriter(&buf),
+		OptionSetWidth(10),
+		OptionSetPredictTime(false),
+		OptionLineMode(0, 0),
+	)
+	bar.Finish()
+	bar.Exit()
+
+	assert.Equal(t, " 100% |██████████|\n", buf.String())
+}
+
+func TestOptionLineModeBprintln(t *testing.T) {
+	buf := strings.Builder{}
+	bar := NewOptions(100, OptionSetWriter(&buf), OptionSetWidth(10), OptionLineMode(50, 0))
+	bar.Add(1)
+	Bprintln(bar, "hello")
+	assert.True(t, strings.HasSuffix(buf.String(), "\nhello\n"), "expected the text to be printed right away, got %q", buf.String())
+}
+
+func TestOptionLineModeIfNotTerminal(t *testing.T) {
+	bar := NewOptions(100, OptionSetWriter(io.Discard), OptionLineModeIfNotTerminal(10, time.Second))
+	_, ok := bar.config.renderer.(*lineRenderer)
+	assert.True(t, ok, "expected line mode for a writer that is not a terminal")
+
+	oldIsTerminal := isTerminal
+	isTerminal = func(w io.Writer) bool {
+		return true
+	}
+	defer func() {
+		isTerminal = oldIsTerminal
+	}()
+	bar = NewOptions(100, OptionSetWriter(os.Stderr), OptionLineModeIfNotTerminal(10, time.Second))
+	assert.Equal(t, TerminalRenderer, bar.config.renderer)
+}
@@ -0,0 +1,93 @@
+package progressbar
+
+import (
+	"context"
+	"io"
+	"log/slog"
+)
+
+// printAbove calls write, which writes to the terminal, so that its output
+// ends up above the bar: the bar is erased first and redrawn afterwards.
+// this function is not thread-safe, so it must be called with an acquired lock.
+func (p *ProgressBar) printAbove(write func() error) error {
+	if p.config.multi != nil {
+		return p.config.multi.printAbove(write)
+	}
+	if !shouldCacheOutput(p) || p.state.maxLineWidth == 0 {
+		// the bar is not on the current line
+		return write()
+	}
+
+	if err := clearProgressBar(p.config, p.state); err != nil {
+		return err
+	}
+	err := write()
+	w, drawErr := p.draw()
+	if w > p.state.maxLineWidth {
+		p.state.maxLineWidth = w
+	}
+	if err != nil {
+		return err
+	}
+	return drawErr
+}
+
+// logWriter is the io.Writer returned by LogWriter
+type logWriter struct {
+	p *ProgressBar
+}
+
+func (w logWriter) Write(b []byte) (int, error) {
+	w.p.lock.Lock()
+	defer w.p.lock.Unlock()
+
+	n := 0
+	err := w.p.printAbove(func() (err error) {
+		n, err = w.p.config.writer.Write(b)
+		return err
+	})
+	return n, err
+}
+
+// LogWriter returns an io.Writer that prints to the writer of the bar, above
+// the bar, without breaking it. It suits the standard log package:
+//
+//	log.SetOutput(bar.LogWriter())
+func (p *ProgressBar) LogWriter() io.Writer {
+	return logWriter{p: p}
+}
+
+// slogHandler is the slog.Handler returned by NewSlogHandler
+type slogHandler struct {
+	bar   *ProgressBar
+	inner slog.Handler
+}
+
+// NewSlogHandler returns a slog.Handler that passes the records to inner, so
+// that the lines inner writes end up above the bar without breaking it.
+// inner should write to the same terminal as the bar, but not to the bar or
+// to its LogWriter.
+func NewSlogHandler(bar *ProgressBar, inner slog.Handler) slog.Handler {
+	return &slogHandler{bar: bar, inner: inner}
+}
+
+func (h *slogHandler) Enabled(ctx context.Context, level slog.Level) bool {
+	return h.inner.Enabled(ctx, level)
+}
+
+func (h *slogHandler) Handle(ctx context.Context, r slog.Record) error {
+	h.bar.lock.Lock()
+	defer h.bar.lock.Unlock()
+
+	return h.bar.printAbove(func() error {
+		return h.inner.Handle(ctx, r)
+	})
+}
+
+func (h *slogHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	return &slogHandler{bar: h.bar, inner: h.inner.WithAttrs(attrs)}
+}
+
+func (h *slogHandler) WithGroup(name string) slog.Handler {
+	return &slogHandler{bar: h.bar, inner: h.inner.WithGroup(name)}
+}
@@ -0,0 +1,65 @@
+package progressbar
+
+import (
+	"bytes"
+	"log"
+	"log/slog"
+	"strings"
+	"testing"
+
+	"github.com/chengxilo/virtualterm"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLogWriter(t *testing.T) {
+	buf := strings.Builder{}
+	bar := NewOptions(100, OptionSetWriter(&buf), OptionSetWidth(10), OptionSetPredictTime(false))
+	bar.Add(10)
+
+	logger := log.New(bar.LogWriter(), "", 0)
+	logger.Println("hello")
+
+	result, _ := virtualterm.Process(buf.String())
+	lines := strings.Split(result, "\n")
+	assert.Len(t, lines, 2)
+	assert.Equal(t, "hello", strings.TrimRight(lines[0], " "))
+	assert.Equal(t, "  10% |█         |", strings.TrimRight(lines[1], " "))
+}
+
+func TestLogWriterNotStarted(t *testing.T) {
+	buf := strings.Builder{}
+	bar := NewOptions(100, OptionSetWriter(&buf))
+	bar.LogWriter().Write([]byte("hello\n"))
+	assert.Equal(t, "hello\n", buf.String())
+}
+
+func TestNewSlogHandler(t *testing.T) {
+	buf := strings.Builder{}
+	bar := NewOptions(100, OptionSetWriter(&buf), OptionSetWidth(10), OptionSetPredictTime(false))
+	bar.Add(10)
+
+	inner := slog.NewTextHandler(&buf, &slog.HandlerOptions{
+		ReplaceAttr: func(groups []string, a slog.Attr) slog.Attr {
+			if a.Key == slog.TimeKey {
+				return slog.Attr{}
+			}
+			return a
+		},
+	})
+	logger := slog.New(NewSlogHandler(bar, inner)).With("file", "a.txt")
+	logger.Info("copied")
+	bar.Add(10)
+
+	result, _ := virtualterm.Process(buf.String())
+	assert.Equal(t, "level=INFO msg=copied file=a.txt\n  20% |██        |", strings.TrimRight(result, " "))
+}
+
+func TestLogWriterMultiProgress(t *testing.T) {
+	buf := &bytes.Buffer{}
+	mp := NewMultiProgress(buf)
+	bar := mp.New(10, OptionSetWidth(10))
+	bar.Add(1)
+	bar.LogWriter().Write([]byte("hello\n"))
+
+	assert.True(t, strings.HasSuffix(buf.String(), "\033[1A\r\033[Jhello\n\r\033[2K  10% |█         |  [0s:0s]\n\033[J"), "got %q", buf.String())
+}
@@ -0,0 +1,82 @@
+package progressbar
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+)
+
+// metricSeries is the labeled frame of a bar, as exported by the metrics
+type metricSeries struct {
+	// labels is the formatted label set, e.g. {description="copying"}
+	labels string
+	frame  Frame
+}
+
+// metrics are the gauges exported for every bar, in order
+var metrics = []struct {
+	name, help string
+	value      func(f Frame) float64
+}{
+	{"progressbar_current", "Current count of the progress bar.", func(f Frame) float64 { return float64(f.CurrentNum) }},
+	{"progressbar_max", "Max of the progress bar, or -1 if the length is unknown.", func(f Frame) float64 { return float64(f.Max) }},
+	{"progressbar_rate", "Rolling average rate, in units per second.", func(f Frame) float64 { return f.Rate }},
+	{"progressbar_eta_seconds", "Predicted number of seconds left.", func(f Frame) float64 { return f.ETA.Seconds() }},
+}
+
+// MetricsHandler returns an http.Handler that exports the bar as gauges in
+// the Prometheus text format, labeled with the description of the bar. It is
+// served at /metrics by StartHTTPServer.
+func (p *ProgressBar) MetricsHandler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		p.lock.Lock()
+		f := p.frame()
+		p.lock.Unlock()
+
+		w.Header().Set("Content-Type", "text/plain; version=0.0.4; charset=utf-8")
+		writeMetrics(w, []metricSeries{{
+			labels: formatLabels("description", f.Description),
+			frame:  f,
+		}})
+	})
+}
+
+// writeMetrics writes the gauges of every series in the Prometheus text
+// format, where the samples of a metric must follow its HELP and TYPE lines.
+func writeMetrics(w io.Writer, series []metricSeries) error {
+	var b strings.Builder
+	for _, m := range metrics {
+		fmt.Fprintf(&b, "# HELP %s %s\n# TYPE %s gauge\n", m.name, m.help, m.name)
+		for _, s := range series {
+			b.WriteString(m.name)
+			b.WriteString(s.labels)
+			b.WriteByte(' ')
+			b.WriteString(strconv.FormatFloat(m.value(s.frame), 'g', -1, 64))
+			b.WriteByte('\n')
+		}
+	}
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+// formatLabels formats the name and value pairs as a label set
+func formatLabels(pairs ...string) string {
+	var b strings.Builder
+	b.WriteByte('{')
+	for i := 0; i+1 < len(pairs); i += 2 {
+		if i > 0 {
+			b.WriteByte(',')
+		}
+		b.WriteString(pairs[i])
+		b.WriteString(`="`)
+		b.WriteString(labelEscaper.Replace(pairs[i+1]))
+		b.WriteByte('"')
+	}
+	b.WriteByte('}')
+	return b.String()
+}
+
+// labelEscaper escapes a label value as required by the text format
+var labelEscaper = strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`)
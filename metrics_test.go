@@ -0,0 +1,38 @@
+package progressbar
+
+import (
+	"io"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMetricsHandler(t *testing.T) {
+	bar := NewOptions(100, OptionSetWriter(io.Discard), OptionSetDescription("copying \"data\"\n"))
+	bar.Add(40)
+
+	rec := httptest.NewRecorder()
+	bar.MetricsHandler().ServeHTTP(rec, httptest.NewRequest("GET", "/metrics", nil))
+	assert.Equal(t, "text/plain; version=0.0.4; charset=utf-8", rec.Header().Get("Content-Type"))
+
+	body := rec.Body.String()
+	labels := `{description="copying \"data\"\n"}`
+	assert.Contains(t, body, "# HELP progressbar_current Current count of the progress bar.\n# TYPE progressbar_current gauge\n")
+	assert.Contains(t, body, "progressbar_current"+labels+" 40\n")
+	assert.Contains(t, body, "progressbar_max"+labels+" 100\n")
+	assert.Contains(t, body, "progressbar_rate"+labels+" ")
+	assert.Contains(t, body, "progressbar_eta_seconds"+labels+" ")
+	assert.Equal(t, 12, strings.Count(body, "\n"))
+}
+
+func TestMetricsHandlerUnknownLength(t *testing.T) {
+	bar := NewOptions(-1, OptionSetWriter(io.Discard))
+	bar.Add(5)
+
+	rec := httptest.NewRecorder()
+	bar.MetricsHandler().ServeHTTP(rec, httptest.NewRequest("GET", "/metrics", nil))
+	assert.Contains(t, rec.Body.String(), "progressbar_max{description=\"\"} -1\n")
+	assert.Contains(t, rec.Body.String(), "progressbar_eta_seconds{description=\"\"} 0\n")
+}
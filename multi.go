@@ -0,0 +1,161 @@
+package progressbar
+
+import (
+	"fmt"
+	"io"
+	"strings"
+	"sync"
+)
+
+// MultiProgress renders several progress bars at once as a stacked block of
+// lines. It owns the writer: the bars it hands out never write to it directly,
+// instead every time one of them renders, the whole block is redrawn in place
+// using ANSI cursor movement.
+//
+// Each line of the block should fit in the terminal, otherwise the terminal
+// wraps it and the block is no longer redrawn in place.
+type MultiProgress struct {
+	writer io.Writer
+	lock   sync.Mutex
+
+	bars  []*ProgressBar
+	lines map[*ProgressBar]string
+
+	// drawn is the number of lines the block currently occupies on screen
+	drawn int
+}
+
+// NewMultiProgress returns a new MultiProgress that renders its bars to w.
+func NewMultiProgress(w io.Writer) *MultiProgress {
+	return &MultiProgress{
+		writer: w,
+		lines:  make(map[*ProgressBar]string),
+	}
+}
+
+// New returns a new ProgressBar with the specified maximum, rendered as part
+// of the MultiProgress.
+func (mp *MultiProgress) New(max int, options ...Option) *ProgressBar {
+	return mp.New64(int64(max), options...)
+}
+
+// New64 returns a new ProgressBar with the specified maximum, rendered as part
+// of the MultiProgress. OptionSetWriter has no effect on the returned bar.
+func (mp *MultiProgress) New64(max int64, options ...Option) *ProgressBar {
+	return NewOptions64(max, withOptions(options, optionMultiProgress(mp))...)
+}
+
+// optionMultiProgress attaches the bar to mp. It is always applied last so
+// the bar is registered before its first render.
+func optionMultiProgress(mp *MultiProgress) Option {
+	return func(p *ProgressBar) {
+		p.config.writer = mp.writer
+		p.config.multi = mp
+		mp.lock.Lock()
+		mp.bars = append(mp.bars, p)
+		mp.lock.Unlock()
+	}
+}
+
+// Remove takes the bar out of the block. The bar keeps working, but it is no
+// longer rendered.
+func (mp *MultiProgress) Remove(bar *ProgressBar) {
+	mp.lock.Lock()
+	defer mp.lock.Unlock()
+
+	for i, b := range mp.bars {
+		if b == bar {
+			mp.bars = append(mp.bars[:i], mp.bars[i+1:]...)
+			break
+		}
+	}
+	delete(mp.lines, bar)
+	mp.redraw("")
+}
+
+// Bars returns the bars currently rendered by the MultiProgress, in order.
+func (mp *MultiProgress) Bars() []*ProgressBar {
+	mp.lock.Lock()
+	defer mp.lock.Unlock()
+
+	return append([]*ProgressBar(nil), mp.bars...)
+}
+
+// update stores the latest rendered line of bar and redraws the block.
+// text is printed above the block, if not empty. It is called with the
+// lock of bar acquired, so it must never try to lock a bar itself.
+func (mp *MultiProgress) update(bar *ProgressBar, line, text string) {
+	mp.lock.Lock()
+	defer mp.lock.Unlock()
+
+	registered := false
+	for _, b := range mp.bars {
+		if b == bar {
+			registered = true
+			break
+		}
+	}
+	if !registered {
+		if text != "" {
+			mp.redraw(text)
+		}
+		return
+	}
+
+	line = strings.TrimPrefix(line, "\r")
+	line = strings.TrimSuffix(line, "\033[0K")
+	mp.lines[bar] = line
+	mp.redraw(text)
+}
+
+// redraw moves the cursor back to the top of the block and draws every bar
+// that has rendered at least once. this function is not thread-safe, so it
+// must be called with an acquired lock.
+func (mp *MultiProgress) redraw(text string) {
+	var b strings.Builder
+	if mp.drawn > 0 {
+		b.WriteString(fmt.Sprintf("\033[%dA", mp.drawn))
+	}
+	b.WriteString("\r")
+	if text != "" {
+		// clear the whole block, so the text does not mix with the old lines
+		b.WriteString("\033[J")
+		b.WriteString(text)
+		if !strings.HasSuffix(text, "\n") {
+			b.WriteString("\n")
+		}
+	}
+
+	drawn := 0
+	for _, bar := range mp.bars {
+		line, ok := mp.lines[bar]
+		if !ok {
+			continue
+		}
+		b.WriteString("\033[2K")
+		b.WriteString(line)
+		b.WriteString("\n")
+		drawn++
+	}
+	// erase the lines left over by bars that have been removed
+	b.WriteString("\033[J")
+	mp.drawn = drawn
+
+	io.WriteString(mp.writer, b.String())
+}
+
+// printAbove erases the block, calls write, which writes to the terminal,
+// and redraws the block below its output. It is called with the lock of a
+// bar acquired, so it must never try to lock a bar itself.
+func (mp *MultiProgress) printAbove(write func() error) error {
+	mp.lock.Lock()
+	defer mp.lock.Unlock()
+
+	if mp.drawn > 0 {
+		io.WriteString(mp.writer, fmt.Sprintf("\033[%dA\r\033[J", mp.drawn))
+		mp.drawn = 0
+	}
+	err := write()
+	mp.redraw("")
+	return err
+}
@@ -0,0 +1,113 @@
+package progressbar
+
+import (
+	"bytes"
+	"strings"
+	"sync"
+	"testing"
+)
+
+func TestMultiProgress(t *testing.T) {
+	buf := &bytes.Buffer{}
+	mp := NewMultiProgress(buf)
+	bar1 := mp.New(10, OptionSetWidth(10), OptionSetDescription("one"))
+	bar2 := mp.New(10, OptionSetWidth(10), OptionSetDescription("two"))
+
+	bar1.Add(5)
+	bar2.Add(2)
+
+	out := buf.String()
+	if !strings.Contains(out, "\033[1A") {
+		t.Errorf("expected the block to be redrawn in place, got %q", out)
+	}
+	last := out[strings.LastIndex(out, "\033[1A"):]
+	if !strings.Contains(last, "one  50%") || !strings.Contains(last, "two  20%") {
+		t.Errorf("expected both bars in the last frame, got %q", last)
+	}
+	if strings.Index(last, "one") > strings.Index(last, "two") {
+		t.Errorf("expected the bars in the order they were created, got %q", last)
+	}
+	if len(mp.Bars()) != 2 {
+		t.Errorf("expected 2 bars, got %d", len(mp.Bars()))
+	}
+}
+
+func TestMultiProgressRemove(t *testing.T) {
+	buf := &bytes.Buffer{}
+	mp := NewMultiProgress(buf)
+	bar1 := mp.New(10, OptionSetWidth(10), OptionSetDescription("one"))
+	bar2 := mp.New(10, OptionSetWidth(10), OptionSetDescription("two"))
+	bar1.Add(1)
+	bar2.Add(1)
+
+	mp.Remove(bar1)
+	buf.Reset()
+	bar1.Add(1)
+	if buf.Len() != 0 {
+		t.Errorf("expected a removed bar not to be rendered, got %q", buf.String())
+	}
+	bar2.Add(1)
+	if strings.Contains(buf.String(), "one") || !strings.Contains(buf.String(), "two  20%") {
+		t.Errorf("expected only the remaining bar, got %q", buf.String())
+	}
+	if strings.Contains(buf.String(), "\033[2A") {
+		t.Errorf("expected the block to shrink to one line, got %q", buf.String())
+	}
+}
+
+func TestMultiProgressFinish(t *testing.T) {
+	buf := &bytes.Buffer{}
+	mp := NewMultiProgress(buf)
+	bar1 := mp.New(10, OptionSetWidth(10), OptionSetDescription("one"))
+	bar2 := mp.New(10, OptionSetWidth(10), OptionSetDescription("two"), OptionClearOnFinish())
+	bar1.Add(1)
+	bar2.Add(1)
+
+	bar1.Finish()
+	bar2.Finish()
+	if len(mp.Bars()) != 1 {
+		t.Errorf("expected the bar cleared on finish to be removed, got %d bars", len(mp.Bars()))
+	}
+
+	buf.Reset()
+	bar3 := mp.New(10, OptionSetWidth(10), OptionSetDescription("three"))
+	bar3.Add(3)
+	if !strings.Contains(buf.String(), "one 100%") || !strings.Contains(buf.String(), "three  30%") {
+		t.Errorf("expected the finished bar to stay in the block, got %q", buf.String())
+	}
+}
+
+func TestMultiProgressBprintln(t *testing.T) {
+	buf := &bytes.Buffer{}
+	mp := NewMultiProgress(buf)
+	bar := mp.New(10, OptionSetWidth(10))
+	bar.Add(1)
+	Bprintln(bar, "hello")
+	bar.Add(1)
+
+	out := buf.String()
+	if !strings.Contains(out, "\033[Jhello\n\033[2K") {
+		t.Errorf("expected the text above the block, got %q", out)
+	}
+}
+
+func TestMultiProgressConcurrency(t *testing.T) {
+	mp := NewMultiProgress(&bytes.Buffer{})
+	var wg sync.WaitGroup
+	for i := 0; i < 5; i++ {
+		bar := mp.New(1000)
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for j := 0; j < 1000; j++ {
+				bar.Add(1)
+			}
+		}()
+	}
+	wg.Wait()
+	for _, bar := range mp.Bars() {
+		if !bar.IsFinished() {
+			t.Errorf("expected every bar to be finished")
+		}
+	}
+}
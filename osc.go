@@ -0,0 +1,75 @@
+package progressbar
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"strings"
+)
+
+// the states of the OSC 9;4 progress sequence
+const (
+	oscProgressClear         = 0
+	oscProgressNormal        = 1
+	oscProgressError         = 2
+	oscProgressIndeterminate = 3
+	oscProgressWarning       = 4
+)
+
+// OptionTerminalProgress reports the progress to the terminal with the
+// OSC 9;4 escape sequence, which Windows Terminal, ConEmu, WezTerm and
+// recent GNOME terminals show on the tab or the taskbar. The progress is
+// shown as indeterminate if the length is unknown, as paused while the bar
+// is paused, as an error once the bar exits, and it is cleared when the bar
+// finishes. Inside tmux, the sequence is wrapped for passthrough, which
+// requires the allow-passthrough option of tmux.
+//
+// It only works with the terminal renderer, and terminals without support
+// ignore the sequence.
+func OptionTerminalProgress(enabled bool) Option {
+	return func(p *ProgressBar) {
+		p.config.terminalProgress = enabled
+		p.config.tmux = os.Getenv("TMUX") != ""
+	}
+}
+
+// writeTerminalProgress writes the OSC 9;4 sequence for the frame, if it
+// changed since the last one. this function is not thread-safe, so it must
+// be called with an acquired lock.
+func (p *ProgressBar) writeTerminalProgress(f Frame) error {
+	if !p.config.terminalProgress {
+		return nil
+	}
+
+	st, pr := oscProgressNormal, int(f.CurrentPercent*100)
+	if p.config.ignoreLength {
+		pr = 0
+	}
+	switch {
+	case f.Exited:
+		st = oscProgressError
+	case f.Finished:
+		st, pr = oscProgressClear, 0
+	case f.Paused:
+		st = oscProgressWarning
+	case p.config.ignoreLength:
+		st = oscProgressIndeterminate
+	}
+	pr = max(0, min(100, pr))
+
+	seq := fmt.Sprintf("\033]9;4;%d;%d\a", st, pr)
+	if seq == p.state.lastTerminalProgress {
+		return nil
+	}
+	p.state.lastTerminalProgress = seq
+	if p.config.tmux {
+		seq = tmuxPassthrough(seq)
+	}
+	_, err := io.WriteString(p.config.writer, seq)
+	return err
+}
+
+// tmuxPassthrough wraps seq so tmux passes it through to the terminal
+func tmuxPassthrough(seq string) string {
+	return "\033Ptmux;" + strings.ReplaceAll(seq, "\033", "\033\033") + "\033\\"
+}
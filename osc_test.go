@@ -0,0 +1,67 @@
+package progressbar
+
+import (
+	"regexp"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// oscSequences returns the OSC 9;4 sequences written to out
+func oscSequences(out string) []string {
+	return regexp.MustCompile("\033\\]9;4;[0-9]+;[0-9]+\a").FindAllString(out, -1)
+}
+
+func TestOptionTerminalProgress(t *testing.T) {
+	t.Setenv("TMUX", "")
+	buf := strings.Builder{}
+	bar := NewOptions(100, OptionSetWriter(&buf), OptionTerminalProgress(true))
+	bar.Add(10)
+	bar.Add(0)
+	bar.Pause()
+	bar.Resume()
+	bar.Add(40)
+	bar.Finish()
+	assert.Equal(t, []string{
+		"\033]9;4;1;10\a",
+		"\033]9;4;4;10\a",
+		"\033]9;4;1;10\a",
+		"\033]9;4;1;50\a",
+		"\033]9;4;0;0\a",
+	}, oscSequences(buf.String()))
+}
+
+func TestOptionTerminalProgressExit(t *testing.T) {
+	t.Setenv("TMUX", "")
+	buf := strings.Builder{}
+	bar := NewOptions(100, OptionSetWriter(&buf), OptionTerminalProgress(true))
+	bar.Add(30)
+	bar.Exit()
+	assert.Equal(t, []string{"\033]9;4;1;30\a", "\033]9;4;2;30\a"}, oscSequences(buf.String()))
+}
+
+func TestOptionTerminalProgressIndeterminate(t *testing.T) {
+	t.Setenv("TMUX", "")
+	buf := strings.Builder{}
+	// without the spinner goroutine, which would write to buf concurrently
+	bar := NewOptions(-1, OptionSetWriter(&buf), OptionSetSpinnerChangeInterval(0), OptionTerminalProgress(true))
+	bar.Add(30)
+	bar.Add(30)
+	assert.Equal(t, []string{"\033]9;4;3;0\a"}, oscSequences(buf.String()))
+}
+
+func TestOptionTerminalProgressTmux(t *testing.T) {
+	t.Setenv("TMUX", "/tmp/tmux-0/default,1,0")
+	buf := strings.Builder{}
+	bar := NewOptions(100, OptionSetWriter(&buf), OptionTerminalProgress(true))
+	bar.Add(10)
+	assert.True(t, strings.HasPrefix(buf.String(), "\033Ptmux;\033\033]9;4;1;10\a\033\\"), "got %q", buf.String())
+}
+
+func TestOptionTerminalProgressDisabled(t *testing.T) {
+	buf := strings.Builder{}
+	bar := NewOptions(100, OptionSetWriter(&buf))
+	bar.Add(10)
+	assert.Empty(t, oscSequences(buf.String()))
+}
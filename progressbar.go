@@ -2,6 +2,7 @@ package progressbar
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
 	"errors"
 	"fmt"
@@ -13,6 +14,7 @@ import (
 	"runtime"
 	"strings"
 	"sync"
+	"text/template"
 	"time"
 
 	"github.com/mitchellh/colorstring"
@@ -45,6 +47,7 @@ type state struct {
 	currentPercent    int
 	lastPercent       int
 	currentSaucerSize int
+	lastFill          int // the filled eighths of cells, with Theme.SmoothFill
 	isAltSaucerHead   bool
 
 	lastShown time.Time
@@ -54,16 +57,29 @@ type state struct {
 	counterNumSinceLast int64
 	counterLastTenRates []float64
 	counterRateTimes    []time.Time
-	spinnerIdx          int // the index of spinner
+	estimatorStarted    bool // whether the estimator has observed the start
+	spinnerIdx          int  // the index of spinner
+	animationFrame      int  // the frame of the indeterminate animation, if not driven by time
 
 	maxLineWidth  int
 	currentBytes  float64
 	startingBytes float64 // units already done before the bar started; excluded from the rate
 	finished      bool
 	exit          bool // Progress bar exit halfway
+	completed     bool // finished or exited, but the completion callback has not been called yet
+
+	paused      bool
+	pausedAt    time.Time     // time when the progress bar was paused
+	pausedTotal time.Duration // time spent paused, excluded from the elapsed time
 
 	details []string // details to show,only used when detail row is set to more than 0
 
+	lastTerminalProgress string // the last OSC 9;4 sequence written
+
+	titlePushed   bool      // whether the previous window title has been saved
+	lastTitle     string    // the last window title written
+	lastTitleTime time.Time // when the last window title was written
+
 	rendered string
 }
 
@@ -122,9 +138,6 @@ type config struct {
 	// spinnerType should be a number between 0-75
 	spinnerType int
 
-	// spinnerTypeOptionUsed remembers if the spinnerType was changed manually
-	spinnerTypeOptionUsed bool
-
 	// spinnerChangeInterval the change interval of spinner
 	// if set this attribute to 0, the spinner only change when renderProgressBar was called
 	// for example, each time when Add() was called,which will call renderProgressBar function
@@ -133,6 +146,11 @@ type config struct {
 	// spinner represents the spinner as a slice of string
 	spinner []string
 
+	// spinnerInterval is the preferred interval of the spinner set with
+	// OptionSpinner, used unless spinnerChangeIntervalSet
+	spinnerInterval          time.Duration
+	spinnerChangeIntervalSet bool
+
 	// fullWidth specifies whether to measure and set the bar to a specific width
 	fullWidth bool
 
@@ -154,6 +172,50 @@ type config struct {
 	maxDetailRow int
 
 	stdBuffer bytes.Buffer
+
+	// template is the layout of the line, if set with OptionTemplate
+	template *template.Template
+
+	// estimator estimates the rate, if set with OptionEstimator
+	estimator Estimator
+
+	// renderer turns the state of the bar into output, TerminalRenderer by default
+	renderer Renderer
+
+	// lineModeFallback replaces the renderer if the writer is not a terminal
+	lineModeFallback Renderer
+
+	// multi is the MultiProgress the bar is rendered by, if any
+	multi *MultiProgress
+
+	// parent is the bar that aggregates the progress of this bar, if any
+	parent *ProgressBar
+
+	// checkpoint is where the state is saved, if set with OptionCheckpointFile
+	checkpoint *checkpoint
+
+	// events sends the state to the /events subscribers of StartHTTPServer
+	events *eventHub
+
+	// registry is the Registry the bar is registered in under registryName, if any
+	registry     *Registry
+	registryName string
+
+	// whether to report the progress with the OSC 9;4 sequence, and whether
+	// to wrap it for tmux
+	terminalProgress bool
+	tmux             bool
+
+	// windowTitle is the template of the window title, if set with OptionWindowTitle
+	windowTitle *template.Template
+
+	// indeterminateStyle is the animation shown if the length is unknown
+	indeterminateStyle IndeterminateStyle
+
+	// saucerColor colors the saucer, if set with OptionSaucerGradient or
+	// OptionColorByPercent, with the colors supported by colorDepth
+	saucerColor saucerColor
+	colorDepth  colorDepth
 }
 
 // Theme defines the elements of the bar
@@ -170,6 +232,11 @@ type Theme struct {
 
 	// BarEndFilled is used once the Bar finishes, if set. Otherwise, it defaults to BarEnd.
 	BarEndFilled string
+
+	// SmoothFill draws the last filled cell partially, with one of the eighth blocks
+	// "▏▎▍▌▋▊▉", so the bar moves by an eighth of a cell instead of a whole cell.
+	// It replaces SaucerHead and AltSaucerHead.
+	SmoothFill bool
 }
 
 var (
@@ -186,6 +253,11 @@ var (
 		BarEnd:        "]",
 	}
 
+	// ThemeSmooth is a predefined Theme that fills the bar by eighths of a cell, using Unicode
+	// block elements. It looks like "|███▌    |".
+	// Configure it with OptionSetTheme(ThemeSmooth).
+	ThemeSmooth = Theme{Saucer: "█", SaucerPadding: " ", BarStart: "|", BarEnd: "|", SmoothFill: true}
+
 	// ThemeUnicode is a predefined Theme that uses Unicode characters, displaying a graphic bar.
 	// It looks like "" (rendering will depend on font being used).
 	// It requires special symbols usually found in "nerd fonts" [2], or in Fira Code [1], and other sources.
@@ -207,6 +279,12 @@ var (
 // Option is the type all options need to adhere to
 type Option func(p *ProgressBar)
 
+// withOptions returns options followed by extra, in a new slice, so the
+// backing array of the options of the caller is never written to
+func withOptions(options []Option, extra ...Option) []Option {
+	return append(append(make([]Option, 0, len(options)+len(extra)), options...), extra...)
+}
+
 // OptionSetWidth sets the width of the bar
 func OptionSetWidth(s int) Option {
 	return func(p *ProgressBar) {
@@ -223,22 +301,54 @@ func OptionSetWidth(s int) Option {
 func OptionSetSpinnerChangeInterval(interval time.Duration) Option {
 	return func(p *ProgressBar) {
 		p.config.spinnerChangeInterval = interval
+		p.config.spinnerChangeIntervalSet = true
 	}
 }
 
-// OptionSpinnerType sets the type of spinner used for indeterminate bars
+// OptionSpinnerType sets the type of spinner used for indeterminate bars,
+// between 0 and 75. OptionSpinner and OptionSpinnerByName are more readable,
+// e.g. OptionSpinner(SpinnerDots) instead of OptionSpinnerType(14).
 func OptionSpinnerType(spinnerType int) Option {
 	return func(p *ProgressBar) {
-		p.config.spinnerTypeOptionUsed = true
+		// the latest spinner option wins
+		p.config.spinner = nil
+		p.config.spinnerInterval = 0
 		p.config.spinnerType = spinnerType
 	}
 }
 
+// OptionSpinner sets the spinner used for indeterminate bars, such as
+// SpinnerDots. Like OptionSpinnerType and OptionSpinnerCustom, it can be
+// combined with any other spinner option, the last one wins. The interval of
+// the spinner is used unless OptionSetSpinnerChangeInterval is set.
+//
+// It panics if the spinner has no frames.
+func OptionSpinner(spinner Spinner) Option {
+	if len(spinner.Frames) == 0 {
+		panic("invalid spinner, must have at least one frame")
+	}
+	return func(p *ProgressBar) {
+		p.config.spinner = spinner.Frames
+		p.config.spinnerInterval = spinner.Interval
+	}
+}
+
+// OptionSpinnerByName sets the spinner registered under name, see
+// RegisterSpinner, like OptionSpinner. It panics if there is no such spinner.
+func OptionSpinnerByName(name string) Option {
+	spinner, ok := SpinnerByName(name)
+	if !ok {
+		panic("unknown spinner " + name)
+	}
+	return OptionSpinner(spinner)
+}
+
 // OptionSpinnerCustom sets the spinner used for indeterminate bars to the passed
-// slice of string
+// slice of string. It replaces any spinner set before, see OptionSpinner.
 func OptionSpinnerCustom(spinner []string) Option {
 	return func(p *ProgressBar) {
 		p.config.spinner = spinner
+		p.config.spinnerInterval = 0
 	}
 }
 
@@ -385,13 +495,30 @@ func OptionClearOnFinish() Option {
 	}
 }
 
-// OptionOnCompletion will invoke cmpl function once its finished
+// OptionOnCompletion will invoke cmpl function once its finished.
+// cmpl is called without the lock of the bar, so it can call its methods.
 func OptionOnCompletion(cmpl func()) Option {
 	return func(p *ProgressBar) {
 		p.config.onCompletion = cmpl
 	}
 }
 
+// optionNewlineOnCompletion ends the line of the bar once its finished, so
+// the output that follows starts on a line of its own. Line mode already ends
+// every line it prints.
+func optionNewlineOnCompletion() Option {
+	return func(p *ProgressBar) {
+		p.config.onCompletion = func() {
+			p.lock.Lock()
+			defer p.lock.Unlock()
+
+			if _, ok := p.config.renderer.(*lineRenderer); !ok {
+				io.WriteString(p.config.writer, "\n")
+			}
+		}
+	}
+}
+
 // OptionShowBytes will update the progress bar
 // configuration settings to display/hide kBytes/Sec
 func OptionShowBytes(val bool) Option {
@@ -471,6 +598,7 @@ func NewOptions64(max int64, options ...Option) *ProgressBar {
 			invisible:             false,
 			spinnerChangeInterval: 100 * time.Millisecond,
 			showTotalBytes:        true,
+			renderer:              TerminalRenderer,
 		},
 	}
 
@@ -486,6 +614,14 @@ func NewOptions64(max int64, options ...Option) *ProgressBar {
 		panic("invalid max detail row, must be greater than 0")
 	}
 
+	if b.config.spinnerInterval > 0 && !b.config.spinnerChangeIntervalSet {
+		b.config.spinnerChangeInterval = b.config.spinnerInterval
+	}
+
+	if b.config.lineModeFallback != nil && b.config.renderer == TerminalRenderer && !isTerminal(b.config.writer) {
+		b.config.renderer = b.config.lineModeFallback
+	}
+
 	// ignoreLength if max bytes not known
 	if b.config.max == -1 {
 		b.lengthUnknown()
@@ -505,13 +641,16 @@ func NewOptions64(max int64, options ...Option) *ProgressBar {
 			defer ticker.Stop()
 
 			for range ticker.C {
-				if b.IsFinished() {
+				b.lock.Lock()
+				done := b.state.finished || b.state.exit
+				b.lock.Unlock()
+				if done {
 					return
 				}
 				if b.IsStarted() {
 					b.lock.Lock()
 					b.render()
-					b.lock.Unlock()
+					b.unlock()
 				}
 			}
 		}()
@@ -520,6 +659,15 @@ func NewOptions64(max int64, options ...Option) *ProgressBar {
 	return &b
 }
 
+// elapsed returns the time elapsed since the bar started, excluding the
+// time spent paused
+func (s *state) elapsed() time.Duration {
+	if s.paused {
+		return s.pausedAt.Sub(s.startTime)
+	}
+	return time.Since(s.startTime)
+}
+
 func getBasicState() state {
 	now := time.Now()
 	return state{
@@ -543,22 +691,24 @@ func DefaultBytes(maxBytes int64, description ...string) *ProgressBar {
 	if len(description) > 0 {
 		desc = description[0]
 	}
-	return NewOptions64(
-		maxBytes,
-		OptionSetDescription(desc),
+	return NewOptions64(maxBytes, append(defaultBytesOptions(), OptionSetDescription(desc))...)
+}
+
+// defaultBytesOptions returns the options of DefaultBytes
+func defaultBytesOptions() []Option {
+	return []Option{
 		OptionSetWriter(os.Stderr),
 		OptionShowBytes(true),
 		OptionShowTotalBytes(true),
 		OptionSetWidth(10),
-		OptionThrottle(65*time.Millisecond),
+		OptionThrottle(65 * time.Millisecond),
 		OptionShowCount(),
-		OptionOnCompletion(func() {
-			fmt.Fprint(os.Stderr, "\n")
-		}),
-		OptionSpinnerType(14),
+		optionNewlineOnCompletion(),
+		OptionSpinner(SpinnerDots),
 		OptionFullWidth(),
 		OptionSetRenderBlankState(true),
-	)
+		OptionLineModeIfNotTerminal(10, 10*time.Second),
+	}
 }
 
 // DefaultBytesSilent is the same as DefaultBytes, but does not output anywhere.
@@ -579,7 +729,7 @@ func DefaultBytesSilent(maxBytes int64, description ...string) *ProgressBar {
 		OptionSetWidth(10),
 		OptionThrottle(65*time.Millisecond),
 		OptionShowCount(),
-		OptionSpinnerType(14),
+		OptionSpinner(SpinnerDots),
 		OptionFullWidth(),
 	)
 }
@@ -600,12 +750,11 @@ func Default(max int64, description ...string) *ProgressBar {
 		OptionThrottle(65*time.Millisecond),
 		OptionShowCount(),
 		OptionShowIts(),
-		OptionOnCompletion(func() {
-			fmt.Fprint(os.Stderr, "\n")
-		}),
-		OptionSpinnerType(14),
+		optionNewlineOnCompletion(),
+		OptionSpinner(SpinnerDots),
 		OptionFullWidth(),
 		OptionSetRenderBlankState(true),
+		OptionLineModeIfNotTerminal(10, 10*time.Second),
 	)
 }
 
@@ -627,7 +776,7 @@ func DefaultSilent(max int64, description ...string) *ProgressBar {
 		OptionThrottle(65*time.Millisecond),
 		OptionShowCount(),
 		OptionShowIts(),
-		OptionSpinnerType(14),
+		OptionSpinner(SpinnerDots),
 		OptionFullWidth(),
 	)
 }
@@ -641,7 +790,7 @@ func (p *ProgressBar) String() string {
 // RenderBlank renders the current bar state, you can use this to render a 0% state
 func (p *ProgressBar) RenderBlank() error {
 	p.lock.Lock()
-	defer p.lock.Unlock()
+	defer p.unlock()
 
 	if p.config.invisible {
 		return nil
@@ -682,24 +831,108 @@ func (p *ProgressBar) Reset() {
 // Finish will fill the bar to full
 func (p *ProgressBar) Finish() error {
 	p.lock.Lock()
+	p.resume()
+	var remaining int64
 	p.state.currentNum = p.config.max
 	if !p.config.ignoreLength {
+		remaining = p.config.max - int64(p.state.currentBytes)
 		p.state.currentBytes = float64(p.config.max)
 	}
 	p.lock.Unlock()
+	if p.config.parent != nil && remaining > 0 {
+		p.config.parent.Add64(remaining)
+	}
 	return p.Add(0)
 }
 
-// Exit will exit the bar to keep current state
+// Exit will exit the bar to keep current state.
+// The bar is drawn one last time, marked as aborted, unless it is
+// already finished, in which case Exit does nothing.
 func (p *ProgressBar) Exit() error {
 	p.lock.Lock()
-	defer p.lock.Unlock()
+	defer p.unlock()
 
+	if p.state.exit || p.state.finished {
+		return nil
+	}
+	p.resume()
 	p.state.exit = true
-	if p.config.onCompletion != nil {
-		p.config.onCompletion()
+	var err error
+	if !p.config.invisible {
+		err = p.config.renderer.Render(p.config.writer, p.frame())
 	}
-	return nil
+	if cerr := p.saveCheckpoint(true); err == nil {
+		err = cerr
+	}
+	p.publishEvent("exit")
+	p.registryDone()
+	p.state.completed = true
+	return err
+}
+
+// Pause pauses the bar, for example while waiting for user input. The time
+// spent paused is excluded from the elapsed time and the rate, and the bar
+// shows that it is paused until Resume is called.
+func (p *ProgressBar) Pause() error {
+	p.lock.Lock()
+	defer p.unlock()
+
+	if p.state.paused || p.state.finished || p.state.exit {
+		return nil
+	}
+	p.state.paused = true
+	p.state.pausedAt = time.Now()
+	if !p.IsStarted() || p.config.invisible {
+		return nil
+	}
+	// show the paused bar right away, regardless of the throttle
+	p.state.lastShown = time.Time{}
+	return p.render()
+}
+
+// Resume resumes the bar after Pause
+func (p *ProgressBar) Resume() error {
+	p.lock.Lock()
+	defer p.unlock()
+
+	if !p.state.paused {
+		return nil
+	}
+	p.resume()
+	if !p.IsStarted() || p.config.invisible {
+		return nil
+	}
+	p.state.lastShown = time.Time{}
+	return p.render()
+}
+
+// IsPaused returns true if progress bar is paused
+func (p *ProgressBar) IsPaused() bool {
+	p.lock.Lock()
+	defer p.lock.Unlock()
+
+	return p.state.paused
+}
+
+// resume ends the pause, moving the clocks of the bar forward by the time
+// spent paused. this function is not thread-safe, so it must be called with
+// an acquired lock.
+func (p *ProgressBar) resume() {
+	if !p.state.paused {
+		return
+	}
+	d := time.Since(p.state.pausedAt)
+	if !p.state.startTime.IsZero() {
+		p.state.startTime = p.state.startTime.Add(d)
+	}
+	if !p.state.counterTime.IsZero() {
+		p.state.counterTime = p.state.counterTime.Add(d)
+	}
+	for i := range p.state.counterRateTimes {
+		p.state.counterRateTimes[i] = p.state.counterRateTimes[i].Add(d)
+	}
+	p.state.pausedTotal += d
+	p.state.paused = false
 }
 
 // Add will add the specified amount to the progressbar
@@ -723,20 +956,18 @@ func (p *ProgressBar) Set64(num int64) error {
 // Add64 will add the specified amount to the progressbar
 func (p *ProgressBar) Add64(num int64) error {
 	if p.config.invisible {
+		if p.config.parent != nil {
+			p.config.parent.Add64(num)
+		}
 		return nil
 	}
 	p.lock.Lock()
-	defer p.lock.Unlock()
+	defer p.unlock()
 
 	if p.state.exit {
 		return nil
 	}
 
-	// error out since OptionSpinnerCustom will always override a manually set spinnerType
-	if p.config.spinnerTypeOptionUsed && len(p.config.spinner) > 0 {
-		return errors.New("OptionSpinnerType and OptionSpinnerCustom cannot be used together")
-	}
-
 	if p.config.max == 0 {
 		return errors.New("max must be greater than 0")
 	}
@@ -751,27 +982,54 @@ func (p *ProgressBar) Add64(num int64) error {
 
 	p.state.currentBytes += float64(num)
 
+	// the parent is always locked after its children, never before
+	if p.config.parent != nil {
+		p.config.parent.Add64(num)
+	}
+
 	if p.state.counterTime.IsZero() {
 		p.state.counterTime = time.Now()
 	}
 
+	if p.config.estimator != nil {
+		done := int64(p.state.currentBytes - p.state.startingBytes)
+		if !p.state.estimatorStarted {
+			// observe where the bar started from, like the sampler does
+			p.config.estimator.Observe(p.state.counterTime.Add(-p.state.pausedTotal), done-num)
+			p.state.estimatorStarted = true
+		}
+		now := time.Now()
+		if p.state.paused {
+			now = p.state.pausedAt
+		}
+		p.config.estimator.Observe(now.Add(-p.state.pausedTotal), done)
+	}
+
 	// reset the countdown timer every second to take rolling average
+	// while paused, the sample is taken after Resume moves counterTime forward
 	p.state.counterNumSinceLast += num
-	if time.Since(p.state.counterTime).Seconds() > 0.5 {
+	if !p.state.paused {
 		now := time.Now()
-		rate := float64(p.state.counterNumSinceLast) / time.Since(p.state.counterTime).Seconds()
-		p.state.counterLastTenRates = append(p.state.counterLastTenRates, rate)
-		p.state.counterRateTimes = append(p.state.counterRateTimes, now)
-		p.state.counterLastTenRates, p.state.counterRateTimes = trimRateSamples(
-			p.state.counterLastTenRates, p.state.counterRateTimes, p.config.rateAveragingWindow, now)
-		p.state.counterTime = now
-		p.state.counterNumSinceLast = 0
+		var sampled bool
+		p.state.counterLastTenRates, p.state.counterRateTimes, sampled = sampleRate(
+			p.state.counterLastTenRates, p.state.counterRateTimes, p.config.rateAveragingWindow,
+			p.state.counterTime, p.state.counterNumSinceLast, now)
+		if sampled {
+			p.state.counterTime = now
+			p.state.counterNumSinceLast = 0
+		}
 	}
 
 	percent := float64(p.state.currentNum) / float64(p.config.max)
 	p.state.currentSaucerSize = int(percent * float64(p.config.width))
 	p.state.currentPercent = int(percent * 100)
 	updateBar := p.state.currentPercent != p.state.lastPercent && p.state.currentPercent > 0
+	if p.config.theme.SmoothFill {
+		// a smooth bar also moves by eighths of a cell within a percent
+		fill := int(percent * float64(p.config.width) * 8)
+		updateBar = updateBar || (fill != p.state.lastFill && p.state.currentNum > 0)
+		p.state.lastFill = fill
+	}
 
 	p.state.lastPercent = p.state.currentPercent
 	if p.state.currentNum > p.config.max {
@@ -786,9 +1044,11 @@ func (p *ProgressBar) Add64(num int64) error {
 	return nil
 }
 
-// AddDetail adds a detail to the progress bar. Only used when maxDetailRow is set to a value greater than 0
+// AddDetail adds a detail to the progress bar. Only used when maxDetailRow is set to a value greater than 0,
+// or when the renderer of the bar is a DetailRenderer
 func (p *ProgressBar) AddDetail(detail string) error {
-	if p.config.maxDetailRow == 0 {
+	dr, isDetailRenderer := p.config.renderer.(DetailRenderer)
+	if p.config.maxDetailRow == 0 && !isDetailRenderer {
 		return errors.New("maxDetailRow is set to 0, cannot add detail")
 	}
 	if p.IsFinished() {
@@ -796,7 +1056,27 @@ func (p *ProgressBar) AddDetail(detail string) error {
 	}
 
 	p.lock.Lock()
-	defer p.lock.Unlock()
+	defer p.unlock()
+	if isDetailRenderer {
+		if p.config.maxDetailRow > 0 {
+			p.state.details = append(p.state.details, detail)
+			if len(p.state.details) > p.config.maxDetailRow {
+				p.state.details = p.state.details[1:]
+			}
+		}
+		if p.config.invisible {
+			return nil
+		}
+		return dr.RenderDetail(p.config.writer, p.frame(), detail)
+	}
+	if !p.ownsTerminalLine() {
+		// other renderers get the details along with the rest of the frame
+		p.state.details = append(p.state.details, detail)
+		if len(p.state.details) > p.config.maxDetailRow {
+			p.state.details = p.state.details[1:]
+		}
+		return p.render()
+	}
 	if p.state.details == nil {
 		// if we add a detail before the first add, it will be weird that we have detail but don't have the progress bar in the top.
 		// so when we add the first detail, we will render the progress bar first.
@@ -848,6 +1128,12 @@ func (p *ProgressBar) renderDetails() error {
 
 // Clear erases the progress bar from the current line
 func (p *ProgressBar) Clear() error {
+	p.lock.Lock()
+	defer p.lock.Unlock()
+
+	if err := p.restoreWindowTitle(); err != nil {
+		return err
+	}
 	return clearProgressBar(p.config, p.state)
 }
 
@@ -855,16 +1141,15 @@ func (p *ProgressBar) Clear() error {
 // can be changed on the fly (as for a slow running process).
 func (p *ProgressBar) Describe(description string) {
 	p.lock.Lock()
-	defer p.lock.Unlock()
+	defer p.unlock()
 	p.config.description = description
 	if p.config.invisible {
 		return
 	}
 	// if already finished, re-render with the new description
-	if p.state.finished && !p.config.clearOnFinish && !p.config.useANSICodes {
+	if p.state.finished && p.ownsTerminalLine() && !p.config.clearOnFinish && !p.config.useANSICodes {
 		clearProgressBar(p.config, p.state)
-		io.Copy(p.config.writer, &p.config.stdBuffer)
-		renderProgressBar(p.config, &p.state)
+		p.draw()
 		return
 	}
 	p.render()
@@ -906,6 +1191,7 @@ func (p *ProgressBar) ChangeMax(newMax int) {
 func (p *ProgressBar) ChangeMax64(newMax int64) {
 	p.lock.Lock()
 
+	oldMax := p.knownMax()
 	p.config.max = newMax
 
 	if p.config.showBytes {
@@ -918,8 +1204,12 @@ func (p *ProgressBar) ChangeMax64(newMax int64) {
 	} else {
 		p.lengthKnown(newMax)
 	}
+	added := p.knownMax() - oldMax
 	p.lock.Unlock() // so p.Add can lock
 
+	if p.config.parent != nil && added != 0 {
+		p.config.parent.AddMax64(added)
+	}
 	p.Add(0) // re-render
 }
 
@@ -937,6 +1227,7 @@ func (p *ProgressBar) AddMax(added int) {
 func (p *ProgressBar) AddMax64(added int64) {
 	p.lock.Lock()
 
+	oldMax := p.knownMax()
 	p.config.max += added
 
 	if p.config.showBytes {
@@ -949,11 +1240,91 @@ func (p *ProgressBar) AddMax64(added int64) {
 	} else {
 		p.lengthKnown(p.config.max)
 	}
+	added = p.knownMax() - oldMax
 	p.lock.Unlock() // so p.Add can lock
 
+	if p.config.parent != nil && added != 0 {
+		p.config.parent.AddMax64(added)
+	}
 	p.Add(0) // re-render
 }
 
+// knownMax returns the max of the bar, or 0 if the length is unknown.
+// this function is not thread-safe, so it must be called with an acquired lock.
+func (p *ProgressBar) knownMax() int64 {
+	if p.config.ignoreLength {
+		return 0
+	}
+	return p.config.max
+}
+
+// NewChild returns a new ProgressBar with the specified maximum, whose
+// progress is aggregated into p. See NewChild64.
+func (p *ProgressBar) NewChild(max int, options ...Option) *ProgressBar {
+	return p.NewChild64(int64(max), options...)
+}
+
+// NewChild64 returns a new ProgressBar with the specified maximum, whose
+// progress is aggregated into p: the max of p grows by the max of the child,
+// and everything added to the child is added to p as well. Changing the max
+// of the child adjusts the max of p accordingly.
+//
+// The parent is typically created with a max of 0, so that its max is the
+// sum of the max of its children. If p is rendered by a MultiProgress, so is
+// the child. When the children are created one after another, p finishes
+// whenever all of its children so far are done, and the next child reopens
+// it.
+func (p *ProgressBar) NewChild64(max int64, options ...Option) *ProgressBar {
+	p.lock.Lock()
+	multi := p.config.multi
+	p.lock.Unlock()
+
+	extra := []Option{func(child *ProgressBar) {
+		child.config.parent = p
+	}}
+	if multi != nil {
+		extra = append(extra, optionMultiProgress(multi))
+	}
+	child := NewOptions64(max, withOptions(options, extra...)...)
+
+	child.lock.Lock()
+	childMax, done := child.knownMax(), int64(child.state.currentBytes)
+	child.lock.Unlock()
+
+	p.addChildStart(childMax, done)
+	if childMax != 0 {
+		p.AddMax64(childMax)
+	} else {
+		p.Add(0) // re-render
+	}
+	return child
+}
+
+// addChildStart accounts for a new child of p, with a max of max and done
+// bytes already done, in p and its own parents. The bytes the child starts
+// with are excluded from the rate, like OptionSetStartingBytes. If p has
+// finished with its previous children, it is reopened, so that it finishes
+// again with the new one.
+func (p *ProgressBar) addChildStart(max, done int64) {
+	p.lock.Lock()
+	p.state.currentNum += done
+	p.state.currentBytes += float64(done)
+	p.state.startingBytes += float64(done)
+	if p.state.finished && !p.state.exit && !p.config.ignoreLength && p.state.currentNum < p.config.max+max {
+		p.state.finished = false
+		if p.config.registry != nil {
+			p.config.registry.reopened(p.config.registryName, p)
+		}
+	}
+	parent := p.config.parent
+	p.lock.Unlock()
+
+	// the parent is always locked after its children, never before
+	if parent != nil {
+		parent.addChildStart(max, done)
+	}
+}
+
 // IsFinished returns true if progress bar is completed
 func (p *ProgressBar) IsFinished() bool {
 	p.lock.Lock()
@@ -971,6 +1342,11 @@ func (p *ProgressBar) IsStarted() bool {
 // rendered line width. this function is not thread-safe,
 // so it must be called with an acquired lock.
 func (p *ProgressBar) render() error {
+	// an exited bar has been drawn for the last time
+	if p.state.exit {
+		return nil
+	}
+
 	// make sure that the rendering is not happening too quickly
 	// but always show if the currentNum reaches the max
 	if !p.IsStarted() {
@@ -980,65 +1356,71 @@ func (p *ProgressBar) render() error {
 		return nil
 	}
 
-	if !p.config.useANSICodes {
-		// first, clear the existing progress bar, if not yet finished.
-		if !p.state.finished {
-			err := clearProgressBar(p.config, p.state)
-			if err != nil {
-				return err
-			}
+	if p.state.finished {
+		// when using ANSI codes we don't pre-clean the current line
+		if p.ownsTerminalLine() && p.config.useANSICodes && p.config.clearOnFinish {
+			return clearProgressBar(p.config, p.state)
 		}
+		return nil
 	}
 
 	// check if the progress bar is finished
-	if !p.state.finished && p.state.currentNum >= p.config.max {
+	if p.state.currentNum >= p.config.max {
 		p.state.finished = true
-		if !p.config.clearOnFinish {
-			io.Copy(p.config.writer, &p.config.stdBuffer)
-			renderProgressBar(p.config, &p.state)
+		err := p.config.renderer.Render(p.config.writer, p.frame())
+		if cerr := p.saveCheckpoint(true); err == nil {
+			err = cerr
 		}
-		if p.config.maxDetailRow > 0 {
-			p.renderDetails()
-			// put the cursor back to the last line of the details
-			var lastDetailLength int
-			if len(p.state.details) == 0 {
-				lastDetailLength = 0
-			} else {
-				lastDetailLength = len(p.state.details[len(p.state.details)-1])
-			}
-			writeString(p.config, fmt.Sprintf("\u001B[%dB\r\u001B[%dC", p.config.maxDetailRow, lastDetailLength))
-		}
-		if p.config.onCompletion != nil {
-			p.config.onCompletion()
-		}
-	}
-	if p.state.finished {
-		// when using ANSI codes we don't pre-clean the current line
-		if p.config.useANSICodes && p.config.clearOnFinish {
-			err := clearProgressBar(p.config, p.state)
-			if err != nil {
-				return err
-			}
-		}
-		return nil
+		p.publishEvent("finished")
+		p.registryDone()
+		p.state.completed = true
+		return err
 	}
 
 	// then, re-render the current progress bar
-	io.Copy(p.config.writer, &p.config.stdBuffer)
-	w, err := renderProgressBar(p.config, &p.state)
-	if err != nil {
+	if err := p.config.renderer.Render(p.config.writer, p.frame()); err != nil {
 		return err
 	}
-
-	if w > p.state.maxLineWidth {
-		p.state.maxLineWidth = w
+	if err := p.saveCheckpoint(false); err != nil {
+		return err
 	}
+	p.publishEvent("")
 
 	p.state.lastShown = time.Now()
 
 	return nil
 }
 
+// unlock releases the lock of the bar, then calls the completion callback
+// if the bar has just finished or exited, so that the callback can call the
+// methods of the bar.
+func (p *ProgressBar) unlock() {
+	completed := p.state.completed
+	p.state.completed = false
+	onCompletion := p.config.onCompletion
+	p.lock.Unlock()
+
+	if completed && onCompletion != nil {
+		onCompletion()
+	}
+}
+
+// draw flushes the buffered output and renders the progress bar. When the bar
+// belongs to a MultiProgress, both are handed to it instead of being written
+// directly. this function is not thread-safe, so it must be called with an
+// acquired lock.
+func (p *ProgressBar) draw() (int, error) {
+	if p.config.multi != nil {
+		text := p.config.stdBuffer.String()
+		p.config.stdBuffer.Reset()
+		w, err := renderProgressBar(p.config, &p.state)
+		p.config.multi.update(p, p.state.rendered, text)
+		return w, err
+	}
+	io.Copy(p.config.writer, &p.config.stdBuffer)
+	return renderProgressBar(p.config, &p.state)
+}
+
 // lengthUnknown sets the progress bar to ignore the length
 func (p *ProgressBar) lengthUnknown() {
 	p.config.ignoreLength = true
@@ -1055,6 +1437,12 @@ func (p *ProgressBar) lengthKnown(max int64) {
 func (p *ProgressBar) State() State {
 	p.lock.Lock()
 	defer p.lock.Unlock()
+	return p.currentState()
+}
+
+// currentState returns the current state. this function is not thread-safe,
+// so it must be called with an acquired lock.
+func (p *ProgressBar) currentState() State {
 	s := State{}
 	s.CurrentNum = p.state.currentNum
 	s.Max = p.config.max
@@ -1064,7 +1452,7 @@ func (p *ProgressBar) State() State {
 	s.CurrentPercent = float64(p.state.currentNum) / float64(p.config.max)
 	s.CurrentBytes = p.state.currentBytes
 	if p.IsStarted() {
-		s.SecondsSince = time.Since(p.state.startTime).Seconds()
+		s.SecondsSince = p.state.elapsed().Seconds()
 	} else {
 		s.SecondsSince = 0
 	}
@@ -1082,7 +1470,18 @@ func (p *ProgressBar) State() State {
 // When the progress bar is finished, call `server.Shutdown()` or `server.Close()` to shut it down manually.
 //
 // hostPort specifies the address and port to bind the server to, for example, "0.0.0.0:19999".
+//
+// The /events endpoint streams the state as Server-Sent Events instead of being polled: a message
+// with the JSON state on every render, and a final "finished" or "exit" event. The /metrics endpoint
+// exports the bar in the Prometheus text format, see MetricsHandler. The page at / shows the bar in
+// a browser, updated live, without any external assets.
 func (p *ProgressBar) StartHTTPServer(hostPort string) *http.Server {
+	p.lock.Lock()
+	if p.config.events == nil {
+		p.config.events = newEventHub()
+	}
+	p.lock.Unlock()
+
 	mux := http.NewServeMux()
 
 	// register routes
@@ -1102,6 +1501,11 @@ func (p *ProgressBar) StartHTTPServer(hostPort string) *http.Server {
 		)
 	})
 
+	mux.HandleFunc("/events", p.serveEvents)
+	mux.Handle("/metrics", p.MetricsHandler())
+	mux.HandleFunc("/status", p.serveStatus)
+	mux.HandleFunc("/{$}", serveStatusPage)
+
 	// create the server instance
 	server := &http.Server{
 		Addr:    hostPort,
@@ -1205,7 +1609,7 @@ func renderDeterminateProgressBar(c config, s *state, bar, stats, leftBrac, righ
 	return fmt.Sprintf("\r%s%s", c.description, str)
 }
 
-func fitProgressBarWidth(c config, s *state, barStart, barEnd, stats, leftBrac, rightBrac string) int {
+func fitProgressBarWidth(c config, barStart, barEnd string, line func(bar string) string) int {
 	if c.fullWidth || c.ignoreLength || c.width <= 0 {
 		return c.width
 	}
@@ -1216,7 +1620,7 @@ func fitProgressBarWidth(c config, s *state, barStart, barEnd, stats, leftBrac,
 	}
 
 	bar := barStart + strings.Repeat(c.theme.SaucerPadding, c.width) + barEnd
-	lineWidth := getStringWidth(c, renderDeterminateProgressBar(c, s, bar, stats, leftBrac, rightBrac))
+	lineWidth := getStringWidth(c, line(bar))
 	if lineWidth <= terminalWidth {
 		return c.width
 	}
@@ -1228,89 +1632,100 @@ func fitProgressBarWidth(c config, s *state, barStart, barEnd, stats, leftBrac,
 	return fitWidth
 }
 
-func renderProgressBar(c config, s *state) (int, error) {
-	var sb strings.Builder
+// renderCount returns the iteration count in "current/total" format
+func renderCount(c config, s *state) string {
+	if !c.ignoreLength {
+		if c.showBytes {
+			currentHumanize, currentSuffix := humanizeBytes(s.currentBytes, c.useIECUnits)
+			if currentSuffix == c.maxHumanizedSuffix {
+				if c.showTotalBytes {
+					return fmt.Sprintf("%s/%s%s",
+						currentHumanize, c.maxHumanized, c.maxHumanizedSuffix)
+				}
+				return fmt.Sprintf("%s%s",
+					currentHumanize, c.maxHumanizedSuffix)
+			} else if c.showTotalBytes {
+				return fmt.Sprintf("%s%s/%s%s",
+					currentHumanize, currentSuffix, c.maxHumanized, c.maxHumanizedSuffix)
+			}
+			return fmt.Sprintf("%s%s", currentHumanize, currentSuffix)
+		} else if c.showTotalBytes {
+			return fmt.Sprintf("%.0f/%d", s.currentBytes, c.max)
+		}
+		return fmt.Sprintf("%.0f", s.currentBytes)
+	}
+	if c.showBytes {
+		currentHumanize, currentSuffix := humanizeBytes(s.currentBytes, c.useIECUnits)
+		return fmt.Sprintf("%s%s", currentHumanize, currentSuffix)
+	} else if c.showTotalBytes {
+		return fmt.Sprintf("%.0f/%s", s.currentBytes, "-")
+	}
+	return fmt.Sprintf("%.0f", s.currentBytes)
+}
 
-	averageRate := average(s.counterLastTenRates)
+// renderItsRate returns the iterations rate, in the unit that suits it best
+func renderItsRate(c config, averageRate float64) string {
+	if averageRate > 1 {
+		return fmt.Sprintf("%0.0f %s/s", averageRate, c.iterationString)
+	} else if averageRate*60 > 1 {
+		return fmt.Sprintf("%0.0f %s/min", 60*averageRate, c.iterationString)
+	}
+	return fmt.Sprintf("%0.0f %s/hr", 3600*averageRate, c.iterationString)
+}
+
+// renderBytesRate returns the bytes rate, or "" if there is no rate yet
+func renderBytesRate(c config, averageRate float64) string {
+	if averageRate > 0 && !math.IsInf(averageRate, 1) {
+		currentHumanize, currentSuffix := humanizeBytes(averageRate, c.useIECUnits)
+		return fmt.Sprintf("%s%s/s", currentHumanize, currentSuffix)
+	}
+	return ""
+}
+
+// averageRate returns the rolling average rate, in units per second
+func averageRate(c config, s *state) float64 {
+	if c.estimator != nil && !s.finished {
+		if rate := c.estimator.Rate(); rate > 0 {
+			return rate
+		}
+	}
 	if len(s.counterLastTenRates) == 0 || s.finished {
 		// if no average samples, or if finished,
 		// then average rate should be the total rate
-		if t := time.Since(s.startTime).Seconds(); t > 0 {
-			averageRate = (s.currentBytes - s.startingBytes) / t
-		} else {
-			averageRate = 0
+		if t := s.elapsed().Seconds(); t > 0 {
+			return (s.currentBytes - s.startingBytes) / t
 		}
+		return 0
 	}
+	return average(s.counterLastTenRates)
+}
+
+// eighthBlocks are the partial cells of Theme.SmoothFill, from 1/8 to 7/8
+var eighthBlocks = []string{"▏", "▎", "▍", "▌", "▋", "▊", "▉"}
+
+func renderProgressBar(c config, s *state) (int, error) {
+	averageRate := averageRate(c, s)
 
+	count := renderCount(c, s)
+	bytesRate := renderBytesRate(c, averageRate)
+	itsRate := renderItsRate(c, averageRate)
+
+	var stats []string
 	// show iteration count in "current/total" iterations format
 	if c.showIterationsCount {
-		if sb.Len() == 0 {
-			sb.WriteString("(")
-		} else {
-			sb.WriteString(", ")
-		}
-		if !c.ignoreLength {
-			if c.showBytes {
-				currentHumanize, currentSuffix := humanizeBytes(s.currentBytes, c.useIECUnits)
-				if currentSuffix == c.maxHumanizedSuffix {
-					if c.showTotalBytes {
-						sb.WriteString(fmt.Sprintf("%s/%s%s",
-							currentHumanize, c.maxHumanized, c.maxHumanizedSuffix))
-					} else {
-						sb.WriteString(fmt.Sprintf("%s%s",
-							currentHumanize, c.maxHumanizedSuffix))
-					}
-				} else if c.showTotalBytes {
-					sb.WriteString(fmt.Sprintf("%s%s/%s%s",
-						currentHumanize, currentSuffix, c.maxHumanized, c.maxHumanizedSuffix))
-				} else {
-					sb.WriteString(fmt.Sprintf("%s%s", currentHumanize, currentSuffix))
-				}
-			} else if c.showTotalBytes {
-				sb.WriteString(fmt.Sprintf("%.0f/%d", s.currentBytes, c.max))
-			} else {
-				sb.WriteString(fmt.Sprintf("%.0f", s.currentBytes))
-			}
-		} else {
-			if c.showBytes {
-				currentHumanize, currentSuffix := humanizeBytes(s.currentBytes, c.useIECUnits)
-				sb.WriteString(fmt.Sprintf("%s%s", currentHumanize, currentSuffix))
-			} else if c.showTotalBytes {
-				sb.WriteString(fmt.Sprintf("%.0f/%s", s.currentBytes, "-"))
-			} else {
-				sb.WriteString(fmt.Sprintf("%.0f", s.currentBytes))
-			}
-		}
+		stats = append(stats, count)
 	}
-
 	// show rolling average rate
-	if c.showBytes && averageRate > 0 && !math.IsInf(averageRate, 1) {
-		if sb.Len() == 0 {
-			sb.WriteString("(")
-		} else {
-			sb.WriteString(", ")
-		}
-		currentHumanize, currentSuffix := humanizeBytes(averageRate, c.useIECUnits)
-		sb.WriteString(fmt.Sprintf("%s%s/s", currentHumanize, currentSuffix))
+	if c.showBytes && bytesRate != "" {
+		stats = append(stats, bytesRate)
 	}
-
 	// show iterations rate
 	if c.showIterationsPerSecond {
-		if sb.Len() == 0 {
-			sb.WriteString("(")
-		} else {
-			sb.WriteString(", ")
-		}
-		if averageRate > 1 {
-			sb.WriteString(fmt.Sprintf("%0.0f %s/s", averageRate, c.iterationString))
-		} else if averageRate*60 > 1 {
-			sb.WriteString(fmt.Sprintf("%0.0f %s/min", 60*averageRate, c.iterationString))
-		} else {
-			sb.WriteString(fmt.Sprintf("%0.0f %s/hr", 3600*averageRate, c.iterationString))
-		}
+		stats = append(stats, itsRate)
 	}
-	if sb.Len() > 0 {
-		sb.WriteString(")")
+	statsString := ""
+	if len(stats) > 0 {
+		statsString = "(" + strings.Join(stats, ", ") + ")"
 	}
 
 	leftBrac, rightBrac, saucer, saucerHead := "", "", "", ""
@@ -1319,17 +1734,88 @@ func renderProgressBar(c config, s *state) (int, error) {
 		barEnd = c.theme.BarEndFilled
 	}
 
+	eta := time.Duration((1/averageRate)*(float64(c.max)-float64(s.currentNum))) * time.Second
+	if c.estimator != nil {
+		if left := c.estimator.Remaining(c.max - s.currentNum); left > 0 {
+			eta = left.Truncate(time.Second)
+		}
+	}
+	if eta.Seconds() < 0 {
+		eta = 0 * time.Second
+	}
+	elapsed := time.Duration(s.elapsed().Seconds()) * time.Second
+
 	// show time prediction in "current/total" seconds format
 	switch {
 	case c.predictTime:
-		rightBracNum := (time.Duration((1/averageRate)*(float64(c.max)-float64(s.currentNum))) * time.Second)
-		if rightBracNum.Seconds() < 0 {
-			rightBracNum = 0 * time.Second
-		}
-		rightBrac = rightBracNum.String()
+		rightBrac = eta.String()
 		fallthrough
 	case c.elapsedTime || c.showElapsedTimeOnFinish:
-		leftBrac = (time.Duration(time.Since(s.startTime).Seconds()) * time.Second).String()
+		leftBrac = elapsed.String()
+	}
+	if s.exit || s.paused {
+		// an aborted or paused bar will not finish in time
+		rightBrac = ""
+	}
+
+	var spinner string
+	if c.ignoreLength {
+		selectedSpinner := spinners[c.spinnerType]
+		if len(c.spinner) > 0 {
+			selectedSpinner = c.spinner
+		}
+
+		if c.indeterminateStyle != IndeterminateSpinner {
+			spinner = renderIndeterminate(c, s)
+		} else if c.spinnerChangeInterval != 0 {
+			// if the spinner is changed according to an interval, calculate it
+			spinner = selectedSpinner[int(math.Round(math.Mod(float64(s.elapsed().Nanoseconds()/c.spinnerChangeInterval.Nanoseconds()), float64(len(selectedSpinner)))))]
+		} else {
+			// if the spinner is changed according to the number render was called
+			spinner = selectedSpinner[s.spinnerIdx]
+			s.spinnerIdx = (s.spinnerIdx + 1) % len(selectedSpinner)
+		}
+		// if set add spinner color code
+		if c.spinnerColorCode != "" && c.colorCodes {
+			spinner = "[" + c.spinnerColorCode + "]" + spinner + "[reset]"
+		}
+	}
+
+	// line renders the whole line around the given bar
+	var lineErr error
+	line := func(bar string) string {
+		if c.template != nil {
+			data := TemplateData{
+				Description: c.description,
+				Bar:         bar,
+				Spinner:     spinner,
+				Percent:     fmt.Sprintf("%d%%", s.currentPercent),
+				Count:       count,
+				Rate:        itsRate,
+				Stats:       statsString,
+				Elapsed:     elapsed.String(),
+				ETA:         eta.String(),
+				Current:     s.currentNum,
+				Max:         c.max,
+				Finished:    s.finished,
+				Exited:      s.exit,
+				Paused:      s.paused,
+			}
+			if c.showBytes {
+				data.Rate = bytesRate
+			}
+			if c.ignoreLength {
+				data.Bar = spinner
+				data.Max = -1
+			}
+			var sb strings.Builder
+			sb.WriteString("\r")
+			if err := c.template.Execute(&sb, data); err != nil {
+				lineErr = err
+			}
+			return sb.String()
+		}
+		return renderDeterminateProgressBar(c, s, bar, statsString, leftBrac, rightBrac)
 	}
 
 	if c.fullWidth && !c.ignoreLength {
@@ -1338,28 +1824,45 @@ func renderProgressBar(c config, s *state) (int, error) {
 			width = 80
 		}
 
-		amend := 1 // an extra space at eol
-		switch {
-		case leftBrac != "" && rightBrac != "":
-			amend = 4 // space, square brackets and colon
-		case leftBrac != "" && rightBrac == "":
-			amend = 4 // space and square brackets and another space
-		case leftBrac == "" && rightBrac != "":
-			amend = 3 // space and square brackets
-		}
-		if c.showDescriptionAtLineEnd {
-			amend += 1 // another space
-		}
+		if c.template != nil {
+			c.width = width - getStringWidth(c, line(barStart+barEnd))
+			if c.width < 0 {
+				c.width = 0
+			}
+		} else {
+			amend := 1 // an extra space at eol
+			switch {
+			case leftBrac != "" && rightBrac != "":
+				amend = 4 // space, square brackets and colon
+			case leftBrac != "" && rightBrac == "":
+				amend = 4 // space and square brackets and another space
+			case leftBrac == "" && rightBrac != "":
+				amend = 3 // space and square brackets
+			}
+			if c.showDescriptionAtLineEnd {
+				amend += 1 // another space
+			}
 
-		c.width = width - getStringWidth(c, c.description) - 10 - amend - sb.Len() - len(leftBrac) - len(rightBrac)
+			c.width = width - getStringWidth(c, c.description) - 10 - amend - len(statsString) - len(leftBrac) - len(rightBrac)
+		}
 		s.currentSaucerSize = int(float64(s.currentPercent) / 100.0 * float64(c.width))
 	}
 	if (s.currentSaucerSize > 0 || s.currentPercent > 0) && c.theme.BarStartFilled != "" {
 		barStart = c.theme.BarStartFilled
 	}
-	c.width = fitProgressBarWidth(c, s, barStart, barEnd, sb.String(), leftBrac, rightBrac)
+	c.width = fitProgressBarWidth(c, barStart, barEnd, line)
+	// eighths is the filled part of the last cell, in smooth mode
+	eighths := 0
 	if !c.ignoreLength {
 		s.currentSaucerSize = int(float64(s.currentPercent) / 100.0 * float64(c.width))
+		if c.theme.SmoothFill {
+			// track the fill from the count, which is finer than the percentage
+			fill := math.Max(0, math.Min(1, float64(s.currentNum)/float64(c.max))) * float64(c.width)
+			s.currentSaucerSize = int(fill)
+			if eighths = int((fill - float64(s.currentSaucerSize)) * 8); eighths > 0 {
+				s.currentSaucerSize++
+			}
+		}
 	}
 	if s.currentSaucerSize > 0 {
 		if c.ignoreLength {
@@ -1368,8 +1871,13 @@ func renderProgressBar(c config, s *state) (int, error) {
 			saucer = strings.Repeat(c.theme.Saucer, s.currentSaucerSize-1)
 		}
 
-		// Check if an alternate saucer head is set for animation
-		if c.theme.AltSaucerHead != "" && s.isAltSaucerHead {
+		if c.theme.SmoothFill && !c.ignoreLength {
+			saucerHead = c.theme.Saucer
+			if eighths > 0 {
+				saucerHead = eighthBlocks[eighths-1]
+			}
+		} else if c.theme.AltSaucerHead != "" && s.isAltSaucerHead {
+			// an alternate saucer head is set for animation
 			saucerHead = c.theme.AltSaucerHead
 			s.isAltSaucerHead = false
 		} else if c.theme.SaucerHead == "" || s.currentSaucerSize == c.width {
@@ -1380,6 +1888,15 @@ func renderProgressBar(c config, s *state) (int, error) {
 			saucerHead = c.theme.SaucerHead
 			s.isAltSaucerHead = true
 		}
+
+		if c.saucerColor != nil && c.colorCodes && !c.ignoreLength {
+			cells := make([]string, s.currentSaucerSize)
+			for i := range cells {
+				cells[i] = c.theme.Saucer
+			}
+			cells[len(cells)-1] = saucerHead
+			saucer, saucerHead = colorSaucer(c, s, cells), ""
+		}
 	}
 
 	/*
@@ -1388,6 +1905,8 @@ func renderProgressBar(c config, s *state) (int, error) {
 
 		or if showDescriptionAtLineEnd is enabled
 		% |------        |  (kb/s) (iteration count) (iteration rate) (predict time) Description
+
+		or the layout given with OptionTemplate
 	*/
 
 	repeatAmount := c.width - s.currentSaucerSize
@@ -1397,58 +1916,47 @@ func renderProgressBar(c config, s *state) (int, error) {
 
 	str := ""
 
-	if c.ignoreLength {
-		selectedSpinner := spinners[c.spinnerType]
-		if len(c.spinner) > 0 {
-			selectedSpinner = c.spinner
-		}
-
-		var spinner string
-		if c.spinnerChangeInterval != 0 {
-			// if the spinner is changed according to an interval, calculate it
-			spinner = selectedSpinner[int(math.Round(math.Mod(float64(time.Since(s.startTime).Nanoseconds()/c.spinnerChangeInterval.Nanoseconds()), float64(len(selectedSpinner)))))]
-		} else {
-			// if the spinner is changed according to the number render was called
-			spinner = selectedSpinner[s.spinnerIdx]
-			s.spinnerIdx = (s.spinnerIdx + 1) % len(selectedSpinner)
-		}
-		// if set add spinner color code
-		if c.spinnerColorCode != "" && c.colorCodes {
-			spinner = "[" + c.spinnerColorCode + "]" + spinner + "[reset]"
-		}
+	if c.ignoreLength && c.template != nil {
+		str = line(spinner)
+	} else if c.ignoreLength {
 		if c.elapsedTime {
 			if c.showDescriptionAtLineEnd {
 				str = fmt.Sprintf("\r%s %s [%s] %s ",
 					spinner,
-					sb.String(),
+					statsString,
 					leftBrac,
 					c.description)
 			} else {
 				str = fmt.Sprintf("\r%s %s %s [%s] ",
 					spinner,
 					c.description,
-					sb.String(),
+					statsString,
 					leftBrac)
 			}
 		} else {
 			if c.showDescriptionAtLineEnd {
 				str = fmt.Sprintf("\r%s %s %s ",
 					spinner,
-					sb.String(),
+					statsString,
 					c.description)
 			} else {
 				str = fmt.Sprintf("\r%s %s %s ",
 					spinner,
 					c.description,
-					sb.String())
+					statsString)
 			}
 		}
-	} else if rightBrac == "" {
-		bar := barStart + saucer + saucerHead + strings.Repeat(c.theme.SaucerPadding, repeatAmount) + barEnd
-		str = renderDeterminateProgressBar(c, s, bar, sb.String(), leftBrac, rightBrac)
 	} else {
 		bar := barStart + saucer + saucerHead + strings.Repeat(c.theme.SaucerPadding, repeatAmount) + barEnd
-		str = renderDeterminateProgressBar(c, s, bar, sb.String(), leftBrac, rightBrac)
+		str = line(bar)
+	}
+	if lineErr != nil {
+		return 0, lineErr
+	}
+	if s.exit && c.template == nil {
+		str += "aborted"
+	} else if s.paused && c.template == nil {
+		str += "paused"
 	}
 
 	if c.colorCodes {
@@ -1491,6 +1999,10 @@ func clearProgressBar(c config, s state) error {
 }
 
 func writeString(c config, str string) error {
+	if c.multi != nil {
+		// the MultiProgress owns the writer and draws the bar itself
+		return nil
+	}
 	if _, err := io.WriteString(c.writer, str); err != nil {
 		return err
 	}
@@ -1509,6 +2021,7 @@ func writeString(c config, str string) error {
 type Reader struct {
 	io.Reader
 	bar *ProgressBar
+	ctx context.Context
 }
 
 // NewReader return a new Reader with a given progress bar.
@@ -1519,22 +2032,146 @@ func NewReader(r io.Reader, bar *ProgressBar) Reader {
 	}
 }
 
+// NewReaderContext return a new Reader with a given progress bar, that stops
+// reading once ctx is done: the next Read returns ctx.Err() and exits the bar.
+func NewReaderContext(ctx context.Context, r io.Reader, bar *ProgressBar) Reader {
+	return Reader{
+		Reader: r,
+		bar:    bar,
+		ctx:    ctx,
+	}
+}
+
 // Read will read the data and add the number of bytes to the progressbar
 func (r *Reader) Read(p []byte) (n int, err error) {
+	if err = contextErr(r.ctx, r.bar); err != nil {
+		return 0, err
+	}
 	n, err = r.Reader.Read(p)
 	r.bar.Add(n)
 	return
 }
 
+// WriteTo implements io.WriterTo, so that io.Copy keeps the fast paths of the
+// underlying reader and writer, such as copy_file_range or sendfile between
+// files and sockets, while the bytes are still added to the progressbar.
+func (r *Reader) WriteTo(w io.Writer) (n int64, err error) {
+	if rf, ok := w.(io.ReaderFrom); ok {
+		return readFromInChunks(r.ctx, rf, r.Reader, r.bar)
+	}
+	if wt, ok := r.Reader.(io.WriterTo); ok {
+		if err = contextErr(r.ctx, r.bar); err != nil {
+			return 0, err
+		}
+		return wt.WriteTo(&Writer{Writer: w, bar: r.bar, ctx: r.ctx})
+	}
+	// hide WriteTo from io.Copy, so it does not call it again
+	return io.Copy(w, struct{ io.Reader }{r})
+}
+
 // Close the reader when it implements io.Closer
 func (r *Reader) Close() (err error) {
 	if closer, ok := r.Reader.(io.Closer); ok {
 		err = closer.Close()
 	}
-	r.bar.Finish()
+	if r.ctx == nil || r.ctx.Err() == nil {
+		r.bar.Finish()
+	}
+	return
+}
+
+// Writer is the progressbar io.Writer struct
+type Writer struct {
+	io.Writer
+	bar *ProgressBar
+	ctx context.Context
+}
+
+// NewWriter return a new Writer with a given progress bar.
+func NewWriter(w io.Writer, bar *ProgressBar) Writer {
+	return Writer{
+		Writer: w,
+		bar:    bar,
+	}
+}
+
+// NewWriterContext return a new Writer with a given progress bar, that stops
+// writing once ctx is done: the next Write returns ctx.Err() and exits the bar.
+func NewWriterContext(ctx context.Context, w io.Writer, bar *ProgressBar) Writer {
+	return Writer{
+		Writer: w,
+		bar:    bar,
+		ctx:    ctx,
+	}
+}
+
+// Write will write the data and add the number of bytes to the progressbar
+func (w *Writer) Write(p []byte) (n int, err error) {
+	if err = contextErr(w.ctx, w.bar); err != nil {
+		return 0, err
+	}
+	n, err = w.Writer.Write(p)
+	w.bar.Add(n)
+	return
+}
+
+// ReadFrom implements io.ReaderFrom, so that io.Copy keeps the fast paths of
+// the underlying writer, such as copy_file_range or sendfile between files
+// and sockets, while the bytes are still added to the progressbar.
+func (w *Writer) ReadFrom(r io.Reader) (n int64, err error) {
+	if rf, ok := w.Writer.(io.ReaderFrom); ok {
+		return readFromInChunks(w.ctx, rf, r, w.bar)
+	}
+	// hide ReadFrom from io.Copy, so it does not call it again
+	return io.Copy(struct{ io.Writer }{w}, r)
+}
+
+// Close the writer when it implements io.Closer
+func (w *Writer) Close() (err error) {
+	if closer, ok := w.Writer.(io.Closer); ok {
+		err = closer.Close()
+	}
+	if w.ctx == nil || w.ctx.Err() == nil {
+		w.bar.Finish()
+	}
 	return
 }
 
+// copyChunkSize is the number of bytes transferred by readFromInChunks before
+// the progressbar is updated
+const copyChunkSize = 256 * 1024
+
+// readFromInChunks copies r to rf with rf.ReadFrom, one chunk at a time, and
+// adds every chunk to the progressbar. The chunks are limited with
+// io.LimitedReader, which the fast paths of *os.File and net.Conn support.
+func readFromInChunks(ctx context.Context, rf io.ReaderFrom, r io.Reader, bar *ProgressBar) (n int64, err error) {
+	for {
+		if err = contextErr(ctx, bar); err != nil {
+			return n, err
+		}
+		var m int64
+		m, err = rf.ReadFrom(&io.LimitedReader{R: r, N: copyChunkSize})
+		n += m
+		bar.Add64(m)
+		if err != nil || m < copyChunkSize {
+			// a short chunk means r reached EOF
+			return n, err
+		}
+	}
+}
+
+// contextErr returns the error of ctx, if any, and exits the bar in that case
+func contextErr(ctx context.Context, bar *ProgressBar) error {
+	if ctx == nil {
+		return nil
+	}
+	err := ctx.Err()
+	if err != nil {
+		bar.Exit()
+	}
+	return err
+}
+
 // Write implement io.Writer
 func (p *ProgressBar) Write(b []byte) (n int, err error) {
 	n = len(b)
@@ -1628,8 +2265,16 @@ var termWidth = func(w io.Writer) (width int, err error) {
 	return 0, err
 }
 
+// isTerminal returns true if w is a terminal
+// and can be redefined for testing
+var isTerminal = func(w io.Writer) bool {
+	f, ok := w.(*os.File)
+	return ok && term.IsTerminal(int(f.Fd()))
+}
+
 func shouldCacheOutput(pb *ProgressBar) bool {
-	return !pb.state.finished && !pb.state.exit && !pb.config.invisible
+	// output is only held back while the terminal renderer owns the current line
+	return !pb.state.finished && !pb.state.exit && !pb.config.invisible && pb.ownsTerminalLine()
 }
 
 func Bprintln(pb *ProgressBar, a ...interface{}) (int, error) {
@@ -303,10 +303,20 @@ func TestSpinnerTypeAndCustom(t *testing.T) {
 	for i := 0; i < 10; i++ {
 		time.Sleep(120 * time.Millisecond)
 		err := bar.Add(1)
-		if err == nil {
-			t.Errorf("Successfully tested both spinner options cannot be used together.")
+		if err != nil {
+			t.Errorf("expected the last spinner option to win, got %v", err)
 		}
 	}
+	assert.Nil(t, bar.config.spinner)
+	assert.Equal(t, 9, bar.config.spinnerType)
+
+	bar = NewOptions(-1,
+		OptionSetWriter(io.Discard),
+		OptionSpinnerType(9),
+		OptionSpinnerCustom([]string{"🐰", "🥕"}),
+	)
+	assert.NoError(t, bar.Add(1))
+	assert.Equal(t, []string{"🐰", "🥕"}, bar.config.spinner)
 }
 
 func Test_IsFinished(t *testing.T) {
@@ -552,6 +562,30 @@ func TestOptionSetThemeFilled(t *testing.T) {
 	}
 }
 
+func TestThemeSmooth(t *testing.T) {
+	bar := NewOptions(
+		1000,
+		OptionSetTheme(ThemeSmooth),
+		OptionSetWidth(10),
+		OptionSetWriter(io.Discard),
+		OptionSetPredictTime(false),
+	)
+	bar.Add(356)
+	assert.Equal(t, "\r  35% |███▌      |  ", bar.String())
+
+	// within the same percent, the bar still moves by an eighth of a cell
+	bar.Add(4)
+	assert.Equal(t, "\r  36% |███▌      |  ", bar.String())
+	bar.Add(3)
+	assert.Equal(t, "\r  36% |███▋      |  ", bar.String())
+
+	bar.Add(12)
+	assert.Equal(t, "\r  37% |███▊      |  ", bar.String())
+
+	bar.Finish()
+	assert.Equal(t, "\r 100% |██████████|  ", bar.String())
+}
+
 // TestOptionSetPredictTime ensures that when predict time is turned off, the progress
 // bar is showing the total steps completed of the given max, otherwise the predicted
 // time in seconds is specified.
@@ -1341,3 +1375,321 @@ func TestRateAveragingWindow(t *testing.T) {
 		t.Errorf("windowed: expected more than %d retained samples, got %d", maxLegacyRateSamples, got)
 	}
 }
+
+func TestNewChild(t *testing.T) {
+	parent := NewOptions(0, OptionSetWriter(io.Discard))
+	child1 := parent.NewChild(10, OptionSetWriter(io.Discard))
+	child2 := parent.NewChild64(20, OptionSetWriter(io.Discard))
+	if parent.GetMax() != 30 {
+		t.Errorf("expected the max of the parent to be the sum of its children, got %d", parent.GetMax())
+	}
+
+	child1.Add(5)
+	child2.Add(10)
+	if got := parent.State().CurrentNum; got != 15 {
+		t.Errorf("expected the parent to aggregate the children, got %d", got)
+	}
+
+	child1.ChangeMax(20)
+	if parent.GetMax() != 40 {
+		t.Errorf("expected ChangeMax on a child to adjust the parent, got %d", parent.GetMax())
+	}
+	child2.AddMax(-5)
+	if parent.GetMax() != 35 {
+		t.Errorf("expected AddMax on a child to adjust the parent, got %d", parent.GetMax())
+	}
+
+	child1.Finish()
+	if got := parent.State().CurrentNum; got != 30 {
+		t.Errorf("expected Finish on a child to fill the parent, got %d", got)
+	}
+	if parent.IsFinished() {
+		t.Errorf("expected the parent not to be finished before all of its children")
+	}
+	child2.Finish()
+	if !parent.IsFinished() {
+		t.Errorf("expected the parent to be finished with all of its children")
+	}
+}
+
+func TestWithOptions(t *testing.T) {
+	options := make([]Option, 1, 2)
+	options[0] = OptionSetWidth(10)
+	got := withOptions(options, OptionSetWidth(20), OptionSetWidth(30))
+	assert.Len(t, got, 3)
+	assert.Nil(t, options[:2][1], "expected the options of the caller to be left untouched")
+}
+
+func TestNewChildOneAfterAnother(t *testing.T) {
+	completions := 0
+	parent := NewOptions(0, OptionSetWriter(io.Discard), OptionOnCompletion(func() {
+		completions++
+	}))
+	child1 := parent.NewChild(10, OptionSetWriter(io.Discard))
+	child1.Add(10)
+	if !parent.IsFinished() {
+		t.Errorf("expected the parent to be finished with its only child")
+	}
+
+	child2 := parent.NewChild(10, OptionSetWriter(io.Discard))
+	if parent.IsFinished() {
+		t.Errorf("expected a new child to reopen the parent")
+	}
+	child2.Add(5)
+	if parent.IsFinished() {
+		t.Errorf("expected the parent not to be finished before its new child")
+	}
+	if got := parent.State(); got.CurrentNum != 15 || got.Max != 20 {
+		t.Errorf("expected the parent at 15/20, got %d/%d", got.CurrentNum, got.Max)
+	}
+	child2.Add(5)
+	if !parent.IsFinished() {
+		t.Errorf("expected the parent to be finished with its new child")
+	}
+	if completions != 2 {
+		t.Errorf("expected the parent to complete with each batch of children, got %d completions", completions)
+	}
+}
+
+func TestNewChildStartingBytesGrandparent(t *testing.T) {
+	grandparent := NewOptions(0, OptionSetWriter(io.Discard))
+	parent := grandparent.NewChild(0, OptionSetWriter(io.Discard))
+	parent.NewChild(100, OptionSetWriter(io.Discard), OptionSetStartingBytes(40))
+	if got := grandparent.State(); got.CurrentNum != 40 || got.Max != 100 {
+		t.Errorf("expected the starting bytes of the grandchild in the grandparent, got %d/%d", got.CurrentNum, got.Max)
+	}
+	if grandparent.state.startingBytes != 40 {
+		t.Errorf("expected the starting bytes to be excluded from the rate of the grandparent, got %v", grandparent.state.startingBytes)
+	}
+}
+
+func TestNewChildStartingBytes(t *testing.T) {
+	parent := NewOptions(0, OptionSetWriter(io.Discard))
+	parent.NewChild(100, OptionSetWriter(io.Discard), OptionSetStartingBytes(40))
+	if got := parent.State().CurrentNum; got != 40 {
+		t.Errorf("expected the starting bytes of the child in the parent, got %d", got)
+	}
+	if parent.state.startingBytes != 40 {
+		t.Errorf("expected the starting bytes to be excluded from the rate of the parent, got %v", parent.state.startingBytes)
+	}
+}
+
+func TestNewChildMultiProgress(t *testing.T) {
+	mp := NewMultiProgress(io.Discard)
+	parent := mp.New(0)
+	parent.NewChild(10)
+	if len(mp.Bars()) != 2 {
+		t.Errorf("expected the child to join the MultiProgress of its parent, got %d bars", len(mp.Bars()))
+	}
+}
+
+func TestReaderContext(t *testing.T) {
+	buf := strings.Builder{}
+	bar := NewOptions(1000, OptionSetWriter(&buf), OptionSetWidth(10), OptionSetPredictTime(false))
+	ctx, cancel := context.WithCancel(context.Background())
+	reader := NewReaderContext(ctx, strings.NewReader(strings.Repeat("a", 1000)), bar)
+
+	p := make([]byte, 100)
+	if _, err := reader.Read(p); err != nil {
+		t.Fatal(err)
+	}
+	cancel()
+	n, err := io.Copy(io.Discard, &reader)
+	assert.Equal(t, int64(0), n)
+	assert.ErrorIs(t, err, context.Canceled)
+	reader.Close()
+
+	assert.Equal(t, int64(100), bar.State().CurrentNum)
+	assert.False(t, bar.IsFinished())
+	assert.Equal(t, "\r  10% |█         |  aborted", bar.String())
+}
+
+func TestWriterContext(t *testing.T) {
+	bar := NewOptions(1000, OptionSetWriter(io.Discard))
+	ctx, cancel := context.WithCancel(context.Background())
+	dst := bytes.Buffer{}
+	writer := NewWriterContext(ctx, &dst, bar)
+
+	writer.Write([]byte("hello"))
+	cancel()
+	n, err := writer.Write([]byte("world"))
+	assert.Equal(t, 0, n)
+	assert.ErrorIs(t, err, context.Canceled)
+	assert.Equal(t, "hello", dst.String())
+	assert.Equal(t, int64(5), bar.State().CurrentNum)
+	assert.True(t, bar.state.exit)
+}
+
+func TestExitOnce(t *testing.T) {
+	completions := 0
+	bar := NewOptions(10, OptionSetWriter(io.Discard), OptionOnCompletion(func() {
+		completions++
+	}))
+	bar.Add(1)
+	bar.Exit()
+	bar.Exit()
+	assert.Equal(t, 1, completions)
+}
+
+func TestOnCompletionCallsBar(t *testing.T) {
+	for _, exit := range []bool{false, true} {
+		var bar *ProgressBar
+		cleared := false
+		bar = NewOptions(10, OptionSetWriter(io.Discard), OptionOnCompletion(func() {
+			cleared = bar.Clear() == nil
+		}))
+		done := make(chan struct{})
+		go func() {
+			defer close(done)
+			bar.Add(5)
+			if exit {
+				bar.Exit()
+			} else {
+				bar.Add(5)
+			}
+		}()
+		select {
+		case <-done:
+		case <-time.After(time.Second):
+			t.Fatalf("expected the completion callback to be able to call the bar (exit: %v)", exit)
+		}
+		assert.True(t, cleared)
+	}
+}
+
+func TestPauseResume(t *testing.T) {
+	buf := strings.Builder{}
+	bar := NewOptions(1000, OptionSetWriter(&buf), OptionSetWidth(10), OptionSetPredictTime(false))
+	bar.Add(100)
+
+	bar.Pause()
+	assert.True(t, bar.IsPaused())
+	assert.Equal(t, "\r  10% |█         |  paused", bar.String())
+
+	// pretend the bar has been paused for a minute
+	bar.lock.Lock()
+	bar.state.pausedAt = bar.state.pausedAt.Add(-time.Minute)
+	bar.state.startTime = bar.state.startTime.Add(-time.Minute)
+	bar.state.counterTime = bar.state.counterTime.Add(-time.Minute)
+	bar.lock.Unlock()
+	assert.Less(t, bar.State().SecondsSince, 1.0)
+
+	bar.Add(100)
+	assert.Empty(t, bar.state.counterLastTenRates, "expected no rate sampled while paused")
+
+	bar.Resume()
+	assert.False(t, bar.IsPaused())
+	assert.Less(t, bar.State().SecondsSince, 1.0)
+	assert.GreaterOrEqual(t, bar.state.pausedTotal, time.Minute)
+	assert.Equal(t, "\r  20% |██        |  ", bar.String())
+}
+
+func TestPauseFinish(t *testing.T) {
+	bar := NewOptions(10, OptionSetWriter(io.Discard))
+	bar.Add(1)
+	bar.Pause()
+	bar.Finish()
+	assert.False(t, bar.IsPaused())
+	assert.True(t, bar.IsFinished())
+
+	// pausing a finished bar has no effect
+	bar.Pause()
+	assert.False(t, bar.IsPaused())
+}
+
+// readFromRecorder records the readers passed to ReadFrom
+type readFromRecorder struct {
+	bytes.Buffer
+	readers []io.Reader
+}
+
+func (r *readFromRecorder) ReadFrom(src io.Reader) (int64, error) {
+	r.readers = append(r.readers, src)
+	return r.Buffer.ReadFrom(src)
+}
+
+func TestReaderWriteTo(t *testing.T) {
+	data := strings.Repeat("a", copyChunkSize*2+10)
+	f, err := os.CreateTemp(t.TempDir(), "src")
+	if err != nil {
+		t.Fatal(err)
+	}
+	f.WriteString(data)
+	f.Seek(0, io.SeekStart)
+	defer f.Close()
+
+	bar := NewOptions(len(data), OptionSetWriter(io.Discard))
+	reader := NewReader(f, bar)
+	dst := &readFromRecorder{}
+	n, err := io.Copy(dst, &reader)
+	assert.NoError(t, err)
+	assert.Equal(t, int64(len(data)), n)
+	assert.Equal(t, data, dst.String())
+	assert.True(t, bar.IsFinished())
+
+	// the file is handed to ReadFrom in chunks, so the fast paths still apply
+	assert.Len(t, dst.readers, 3)
+	lr, ok := dst.readers[0].(*io.LimitedReader)
+	assert.True(t, ok)
+	assert.Equal(t, f, lr.R)
+}
+
+func TestReaderWriteToFallback(t *testing.T) {
+	// bytes.Reader implements io.WriterTo
+	bar := NewOptions(5, OptionSetWriter(io.Discard))
+	reader := NewReader(bytes.NewReader([]byte("hello")), bar)
+	dst := strings.Builder{}
+	n, err := io.Copy(struct{ io.Writer }{&dst}, &reader)
+	assert.NoError(t, err)
+	assert.Equal(t, int64(5), n)
+	assert.Equal(t, "hello", dst.String())
+	assert.Equal(t, int64(5), bar.State().CurrentNum)
+
+	// neither side implements a fast path
+	bar = NewOptions(5, OptionSetWriter(io.Discard))
+	reader = NewReader(struct{ io.Reader }{strings.NewReader("hello")}, bar)
+	dst.Reset()
+	n, err = io.Copy(struct{ io.Writer }{&dst}, &reader)
+	assert.NoError(t, err)
+	assert.Equal(t, int64(5), n)
+	assert.Equal(t, int64(5), bar.State().CurrentNum)
+}
+
+func TestWriterReadFrom(t *testing.T) {
+	data := strings.Repeat("a", copyChunkSize+10)
+	f, err := os.CreateTemp(t.TempDir(), "dst")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+
+	bar := NewOptions(len(data), OptionSetWriter(io.Discard))
+	writer := NewWriter(f, bar)
+	n, err := io.Copy(&writer, strings.NewReader(data))
+	assert.NoError(t, err)
+	assert.Equal(t, int64(len(data)), n)
+	assert.True(t, bar.IsFinished())
+
+	got, _ := os.ReadFile(f.Name())
+	assert.Equal(t, data, string(got))
+
+	// the writer does not implement a fast path
+	bar = NewOptions(5, OptionSetWriter(io.Discard))
+	dst := strings.Builder{}
+	writer = NewWriter(struct{ io.Writer }{&dst}, bar)
+	n, err = io.Copy(&writer, strings.NewReader("hello"))
+	assert.NoError(t, err)
+	assert.Equal(t, int64(5), n)
+	assert.Equal(t, "hello", dst.String())
+	assert.Equal(t, int64(5), bar.State().CurrentNum)
+}
+
+func TestWriterReadFromContext(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	bar := NewOptions(10, OptionSetWriter(io.Discard))
+	writer := NewWriterContext(ctx, &bytes.Buffer{}, bar)
+	_, err := io.Copy(&writer, strings.NewReader("hello"))
+	assert.ErrorIs(t, err, context.Canceled)
+	assert.Equal(t, int64(0), bar.State().CurrentNum)
+}
@@ -0,0 +1,241 @@
+package progressbar
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sort"
+	"sync"
+	"time"
+)
+
+// Registry holds named bars, to serve every bar of a process with a single
+// HTTP server, see Handler. A finished bar stays in the registry for the
+// retention time, then it is removed.
+//
+// The registry is locked after a bar, never before, so it does not lock the
+// bars while holding its own lock.
+type Registry struct {
+	lock      sync.Mutex
+	bars      map[string]*ProgressBar
+	retention time.Duration
+	// timers are the pending removals of finished bars, by name
+	timers map[string]*time.Timer
+}
+
+// DefaultRegistry is the registry bars join with OptionRegister. Finished
+// bars are kept for a minute.
+var DefaultRegistry = NewRegistry(time.Minute)
+
+// NewRegistry returns a new Registry that removes finished bars after
+// retention. A negative retention keeps them forever.
+func NewRegistry(retention time.Duration) *Registry {
+	return &Registry{
+		bars:      make(map[string]*ProgressBar),
+		retention: retention,
+		timers:    make(map[string]*time.Timer),
+	}
+}
+
+// OptionRegister adds the bar to DefaultRegistry under name, replacing any
+// bar already registered under that name.
+func OptionRegister(name string) Option {
+	return func(p *ProgressBar) {
+		DefaultRegistry.Register(name, p)
+	}
+}
+
+// SetRetention sets how long finished bars are kept. It applies to the bars
+// that finish afterwards.
+func (r *Registry) SetRetention(retention time.Duration) {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+
+	r.retention = retention
+}
+
+// Register adds bar under name, replacing any bar already registered under
+// that name.
+func (r *Registry) Register(name string, bar *ProgressBar) {
+	bar.lock.Lock()
+	defer bar.lock.Unlock()
+
+	bar.config.registry = r
+	bar.config.registryName = name
+
+	r.lock.Lock()
+	defer r.lock.Unlock()
+
+	r.stopTimer(name)
+	r.bars[name] = bar
+}
+
+// Remove removes the bar registered under name, if any.
+func (r *Registry) Remove(name string) {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+
+	delete(r.bars, name)
+}
+
+// Get returns the bar registered under name, or nil.
+func (r *Registry) Get(name string) *ProgressBar {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+
+	return r.bars[name]
+}
+
+// Names returns the names of the registered bars, sorted.
+func (r *Registry) Names() []string {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+
+	names := make([]string, 0, len(r.bars))
+	for name := range r.bars {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// done removes bar after the retention time, unless another bar has been
+// registered under its name in the meantime. It is called with the lock of
+// bar acquired, when the bar finishes or exits.
+func (r *Registry) done(name string, bar *ProgressBar) {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+
+	if r.bars[name] != bar {
+		return
+	}
+	var timer *time.Timer
+	remove := func() {
+		r.lock.Lock()
+		defer r.lock.Unlock()
+
+		if r.timers[name] == timer {
+			delete(r.timers, name)
+			delete(r.bars, name)
+		}
+	}
+	r.stopTimer(name)
+	switch {
+	case r.retention == 0:
+		delete(r.bars, name)
+	case r.retention > 0:
+		timer = time.AfterFunc(r.retention, remove)
+		r.timers[name] = timer
+	}
+}
+
+// reopened puts bar back under name, if no other bar has been registered
+// under that name since it finished. It is called with the lock of bar
+// acquired, when a parent finished by its children gets a new child.
+func (r *Registry) reopened(name string, bar *ProgressBar) {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+
+	if other, ok := r.bars[name]; ok && other != bar {
+		return
+	}
+	r.stopTimer(name)
+	r.bars[name] = bar
+}
+
+// stopTimer cancels the pending removal of the bar registered under name, if
+// any. this function is not thread-safe, so it must be called with an
+// acquired lock.
+func (r *Registry) stopTimer(name string) {
+	if timer, ok := r.timers[name]; ok {
+		timer.Stop()
+		delete(r.timers, name)
+	}
+}
+
+// registryDone tells the registry, if any, that the bar is done. this
+// function is not thread-safe, so it must be called with an acquired lock.
+func (p *ProgressBar) registryDone() {
+	if p.config.registry != nil {
+		p.config.registry.done(p.config.registryName, p)
+	}
+}
+
+// registeredBar is a bar as listed at /bars
+type registeredBar struct {
+	Name string
+	status
+}
+
+// Handler returns an http.Handler that serves the registered bars:
+//
+//   - /bars lists the bars, sorted by name, with their state
+//   - /bars/{name}/state serves the state of a bar, like /state of StartHTTPServer
+//   - /metrics exports every bar in the Prometheus text format, labeled with
+//     its name and description
+func (r *Registry) Handler() http.Handler {
+	mux := http.NewServeMux()
+
+	mux.HandleFunc("/bars", func(w http.ResponseWriter, req *http.Request) {
+		bars := []registeredBar{}
+		for _, name := range r.Names() {
+			if bar := r.Get(name); bar != nil {
+				bar.lock.Lock()
+				bars = append(bars, registeredBar{Name: name, status: bar.currentStatus()})
+				bar.lock.Unlock()
+			}
+		}
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(bars)
+	})
+
+	mux.HandleFunc("/bars/{name}/state", func(w http.ResponseWriter, req *http.Request) {
+		bar := r.Get(req.PathValue("name"))
+		if bar == nil {
+			http.NotFound(w, req)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(finiteState(bar.State()))
+	})
+
+	mux.HandleFunc("/metrics", func(w http.ResponseWriter, req *http.Request) {
+		var series []metricSeries
+		for _, name := range r.Names() {
+			if bar := r.Get(name); bar != nil {
+				bar.lock.Lock()
+				f := bar.frame()
+				bar.lock.Unlock()
+				series = append(series, metricSeries{
+					labels: formatLabels("name", name, "description", f.Description),
+					frame:  f,
+				})
+			}
+		}
+		w.Header().Set("Content-Type", "text/plain; version=0.0.4; charset=utf-8")
+		writeMetrics(w, series)
+	})
+
+	return mux
+}
+
+// StartHTTPServer starts an HTTP server serving Handler, so a single port
+// serves every registered bar. It works like ProgressBar.StartHTTPServer.
+func (r *Registry) StartHTTPServer(hostPort string) *http.Server {
+	server := &http.Server{
+		Addr:    hostPort,
+		Handler: r.Handler(),
+	}
+
+	go func() {
+		defer func() {
+			if err := recover(); err != nil {
+				fmt.Println("encounter panic: ", err)
+			}
+		}()
+
+		_ = server.ListenAndServe()
+	}()
+
+	return server
+}
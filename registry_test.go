@@ -0,0 +1,97 @@
+package progressbar
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRegistryHandler(t *testing.T) {
+	r := NewRegistry(-1)
+	copying := NewOptions(10, OptionSetWriter(io.Discard), OptionSetDescription("copying"))
+	r.Register("copy", copying)
+	r.Register("index", NewOptions(-1, OptionSetWriter(io.Discard)))
+	copying.Add(3)
+	assert.Equal(t, []string{"copy", "index"}, r.Names())
+
+	server := httptest.NewServer(r.Handler())
+	defer server.Close()
+
+	resp, err := http.Get(server.URL + "/bars")
+	assert.NoError(t, err)
+	var bars []registeredBar
+	assert.NoError(t, json.NewDecoder(resp.Body).Decode(&bars))
+	resp.Body.Close()
+	assert.Len(t, bars, 2)
+	assert.Equal(t, "copy", bars[0].Name)
+	assert.Equal(t, "copying", bars[0].Description)
+	assert.Equal(t, int64(3), bars[0].CurrentNum)
+	assert.Equal(t, int64(-1), bars[1].Max)
+
+	resp, err = http.Get(server.URL + "/bars/copy/state")
+	assert.NoError(t, err)
+	var state State
+	assert.NoError(t, json.NewDecoder(resp.Body).Decode(&state))
+	resp.Body.Close()
+	assert.Equal(t, int64(3), state.CurrentNum)
+
+	resp, err = http.Get(server.URL + "/bars/unknown/state")
+	assert.NoError(t, err)
+	resp.Body.Close()
+	assert.Equal(t, http.StatusNotFound, resp.StatusCode)
+
+	resp, err = http.Get(server.URL + "/metrics")
+	assert.NoError(t, err)
+	body, _ := io.ReadAll(resp.Body)
+	resp.Body.Close()
+	assert.Contains(t, string(body), "progressbar_current{name=\"copy\",description=\"copying\"} 3\n")
+	assert.Contains(t, string(body), "progressbar_max{name=\"index\",description=\"\"} -1\n")
+}
+
+func TestRegistryRetention(t *testing.T) {
+	r := NewRegistry(0)
+	bar := NewOptions(10, OptionSetWriter(io.Discard))
+	r.Register("bar", bar)
+	bar.Finish()
+	assert.Nil(t, r.Get("bar"), "expected the finished bar to be removed right away")
+
+	r.SetRetention(20 * time.Millisecond)
+	bar = NewOptions(10, OptionSetWriter(io.Discard))
+	r.Register("bar", bar)
+	bar.Exit()
+	assert.Equal(t, bar, r.Get("bar"))
+	assert.Eventually(t, func() bool { return r.Get("bar") == nil }, time.Second, 5*time.Millisecond)
+
+	// a bar registered under the same name is not removed with the old one
+	old := NewOptions(10, OptionSetWriter(io.Discard))
+	r.Register("bar", old)
+	old.Finish()
+	bar = NewOptions(10, OptionSetWriter(io.Discard))
+	r.Register("bar", bar)
+	time.Sleep(50 * time.Millisecond)
+	assert.Equal(t, bar, r.Get("bar"))
+}
+
+func TestRegistryReopenedParent(t *testing.T) {
+	r := NewRegistry(20 * time.Millisecond)
+	parent := NewOptions(0, OptionSetWriter(io.Discard))
+	r.Register("overall", parent)
+	parent.NewChild(10, OptionSetWriter(io.Discard)).Add(10)
+	child := parent.NewChild(10, OptionSetWriter(io.Discard))
+	time.Sleep(50 * time.Millisecond)
+	assert.Equal(t, parent, r.Get("overall"), "expected the reopened parent to stay registered")
+
+	child.Add(10)
+	assert.Eventually(t, func() bool { return r.Get("overall") == nil }, time.Second, 5*time.Millisecond)
+}
+
+func TestOptionRegister(t *testing.T) {
+	bar := NewOptions(10, OptionSetWriter(io.Discard), OptionRegister("test-option-register"))
+	defer DefaultRegistry.Remove("test-option-register")
+	assert.Equal(t, bar, DefaultRegistry.Get("test-option-register"))
+}
@@ -0,0 +1,183 @@
+package progressbar
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"time"
+)
+
+// Renderer turns the state of a bar into output. Render is called with a
+// snapshot of the bar every time it renders, once more when it finishes
+// and when it exits. Render is called with the lock of the bar acquired, so
+// it must not call any method of the bar.
+type Renderer interface {
+	Render(w io.Writer, f Frame) error
+}
+
+// RendererFunc is an adapter to use an ordinary function as a Renderer.
+type RendererFunc func(w io.Writer, f Frame) error
+
+// Render calls fn(w, f)
+func (fn RendererFunc) Render(w io.Writer, f Frame) error {
+	return fn(w, f)
+}
+
+// DetailRenderer is implemented by renderers that render each detail added
+// with AddDetail on its own, as soon as it is added. AddDetail does not
+// require OptionSetMaxDetailRow for these renderers.
+type DetailRenderer interface {
+	Renderer
+	RenderDetail(w io.Writer, f Frame, detail string) error
+}
+
+// TerminalLineRenderer is implemented by renderers that draw the bar on its
+// terminal line, like TerminalRenderer and the renderers wrapping it. While
+// OwnsTerminalLine returns true, the bar draws the rows added with AddDetail,
+// Bprintln holds text back until the next render, and Describe redraws a
+// finished bar, as with TerminalRenderer itself.
+type TerminalLineRenderer interface {
+	Renderer
+	OwnsTerminalLine() bool
+}
+
+// Frame is a snapshot of a bar, handed to its Renderer
+type Frame struct {
+	State
+
+	// Rate is the rolling average rate, in units per second
+	Rate float64
+	// ETA is the predicted time left, based on Rate
+	ETA time.Duration
+	// Finished is true for the frame rendered when the bar finishes
+	Finished bool
+	// Exited is true for the frame rendered when the bar exits halfway
+	Exited bool
+	// Paused is true while the bar is paused
+	Paused bool
+	// Details are the details added with AddDetail
+	Details []string
+
+	bar *ProgressBar
+}
+
+// TerminalRenderer is the default Renderer. It draws the bar on a single
+// line of the writer of the bar, set with OptionSetWriter, and redraws it in
+// place with carriage returns or ANSI codes. It always draws on the writer of
+// the bar, the writer passed to Render is ignored.
+//
+// Other renderers can wrap it, and should implement TerminalLineRenderer to
+// be treated like it.
+var TerminalRenderer Renderer = terminalRenderer{}
+
+type terminalRenderer struct{}
+
+func (terminalRenderer) Render(_ io.Writer, f Frame) error {
+	if f.bar == nil {
+		return errors.New("TerminalRenderer can only render frames of a ProgressBar")
+	}
+	return f.bar.renderTerminal(f)
+}
+
+func (terminalRenderer) OwnsTerminalLine() bool {
+	return true
+}
+
+// ownsTerminalLine returns true if the renderer of the bar draws it on its
+// terminal line, see TerminalLineRenderer
+func (p *ProgressBar) ownsTerminalLine() bool {
+	r, ok := p.config.renderer.(TerminalLineRenderer)
+	return ok && r.OwnsTerminalLine()
+}
+
+// OptionRenderer sets the Renderer that turns the state of the bar into
+// output, instead of TerminalRenderer. Only TerminalRenderer and the
+// renderers implementing TerminalLineRenderer draw the rows added with
+// AddDetail, other renderers get them in Frame.Details.
+func OptionRenderer(r Renderer) Option {
+	return func(p *ProgressBar) {
+		p.config.renderer = r
+	}
+}
+
+// frame returns a snapshot of the bar. this function is not thread-safe,
+// so it must be called with an acquired lock.
+func (p *ProgressBar) frame() Frame {
+	f := Frame{
+		State:    p.currentState(),
+		Rate:     averageRate(p.config, &p.state),
+		Finished: p.state.finished,
+		Exited:   p.state.exit,
+		Paused:   p.state.paused,
+		Details:  append([]string(nil), p.state.details...),
+		bar:      p,
+	}
+	if !p.config.ignoreLength {
+		left := p.config.max - p.state.currentNum
+		if p.config.estimator != nil {
+			f.ETA = p.config.estimator.Remaining(left)
+		}
+		if f.ETA <= 0 {
+			f.ETA = remaining(f.Rate, left)
+		}
+	}
+	return f
+}
+
+// renderTerminal draws the frame on the terminal. this function is not
+// thread-safe, so it must be called with an acquired lock.
+func (p *ProgressBar) renderTerminal(f Frame) error {
+	if f.Exited && (f.Finished || !p.IsStarted()) {
+		return nil
+	}
+
+	if err := p.writeTerminalProgress(f); err != nil {
+		return err
+	}
+	if err := p.writeWindowTitle(f); err != nil {
+		return err
+	}
+
+	if !p.config.useANSICodes {
+		// first, clear the existing progress bar
+		err := clearProgressBar(p.config, p.state)
+		if err != nil {
+			return err
+		}
+	}
+
+	if f.Finished {
+		if !p.config.clearOnFinish {
+			p.draw()
+		} else if p.config.multi != nil {
+			p.config.multi.Remove(p)
+		}
+		if p.config.maxDetailRow > 0 {
+			p.renderDetails()
+			// put the cursor back to the last line of the details
+			var lastDetailLength int
+			if len(p.state.details) == 0 {
+				lastDetailLength = 0
+			} else {
+				lastDetailLength = len(p.state.details[len(p.state.details)-1])
+			}
+			writeString(p.config, fmt.Sprintf("\u001B[%dB\r\u001B[%dC", p.config.maxDetailRow, lastDetailLength))
+		}
+		// when using ANSI codes we don't pre-clean the current line
+		if p.config.useANSICodes && p.config.clearOnFinish {
+			return clearProgressBar(p.config, p.state)
+		}
+		return nil
+	}
+
+	// the last frame of an exited bar is drawn like any other, marked as aborted
+	w, err := p.draw()
+	if err != nil {
+		return err
+	}
+
+	if w > p.state.maxLineWidth {
+		p.state.maxLineWidth = w
+	}
+	return nil
+}
@@ -0,0 +1,124 @@
+package progressbar
+
+import (
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestOptionRenderer(t *testing.T) {
+	var frames []Frame
+	completed := false
+	buf := strings.Builder{}
+	bar := NewOptions(10,
+		OptionSetWriter(&buf),
+		OptionOnCompletion(func() { completed = true }),
+		OptionRenderer(RendererFunc(func(w io.Writer, f Frame) error {
+			frames = append(frames, f)
+			return nil
+		})),
+	)
+	bar.Add(5)
+	bar.Describe("halfway")
+	bar.Add(5)
+	bar.Add(1)
+
+	assert.Empty(t, buf.String(), "expected nothing written by the terminal renderer")
+	assert.Len(t, frames, 3)
+	assert.Equal(t, int64(5), frames[0].CurrentNum)
+	assert.Equal(t, "halfway", frames[1].Description)
+	assert.True(t, frames[2].Finished)
+	assert.Equal(t, 1.0, frames[2].CurrentPercent)
+	assert.True(t, completed)
+}
+
+func TestOptionRendererExit(t *testing.T) {
+	var last Frame
+	bar := NewOptions(10,
+		OptionRenderer(RendererFunc(func(w io.Writer, f Frame) error {
+			last = f
+			return nil
+		})),
+	)
+	bar.Add(5)
+	bar.Exit()
+	assert.True(t, last.Exited)
+	assert.Equal(t, int64(5), last.CurrentNum)
+}
+
+func TestOptionRendererDetails(t *testing.T) {
+	var last Frame
+	bar := NewOptions(10,
+		OptionSetMaxDetailRow(2),
+		OptionRenderer(RendererFunc(func(w io.Writer, f Frame) error {
+			last = f
+			return nil
+		})),
+	)
+	bar.AddDetail("one")
+	bar.AddDetail("two")
+	bar.AddDetail("three")
+	assert.Equal(t, []string{"two", "three"}, last.Details)
+}
+
+func TestTerminalRendererWrapped(t *testing.T) {
+	expected := strings.Builder{}
+	bar := NewOptions(10, OptionSetWriter(&expected), OptionSetWidth(10))
+	bar.Add(5)
+	bar.Add(5)
+
+	got := strings.Builder{}
+	calls := 0
+	bar = NewOptions(10,
+		OptionSetWriter(&got),
+		OptionSetWidth(10),
+		OptionRenderer(RendererFunc(func(w io.Writer, f Frame) error {
+			calls++
+			return TerminalRenderer.Render(w, f)
+		})),
+	)
+	bar.Add(5)
+	bar.Add(5)
+
+	assert.Equal(t, 2, calls)
+	assert.Equal(t, expected.String(), got.String())
+	assert.Error(t, TerminalRenderer.Render(io.Discard, Frame{}))
+}
+
+// countingRenderer wraps TerminalRenderer and counts the renders
+type countingRenderer struct {
+	calls int
+}
+
+func (r *countingRenderer) Render(w io.Writer, f Frame) error {
+	r.calls++
+	return TerminalRenderer.Render(w, f)
+}
+
+func (r *countingRenderer) OwnsTerminalLine() bool {
+	return true
+}
+
+func TestTerminalLineRenderer(t *testing.T) {
+	run := func(options ...Option) string {
+		buf := strings.Builder{}
+		bar := NewOptions(10, append([]Option{
+			OptionSetWriter(&buf),
+			OptionSetWidth(10),
+			OptionSetMaxDetailRow(2),
+		}, options...)...)
+		bar.Add(5)
+		bar.AddDetail("one")
+		Bprintln(bar, "hello")
+		bar.Add(5)
+		bar.Describe("done")
+		return buf.String()
+	}
+
+	r := &countingRenderer{}
+	got := run(OptionRenderer(r))
+	assert.Greater(t, r.calls, 0)
+	assert.Equal(t, run(), got)
+}
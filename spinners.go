@@ -1,5 +1,108 @@
 package progressbar
 
+import (
+	"sort"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// Spinner is the animation shown by bars whose length is unknown
+type Spinner struct {
+	// Frames are shown one after the other
+	Frames []string
+	// Interval is the preferred time between frames. It replaces the default
+	// of OptionSetSpinnerChangeInterval, if not 0.
+	Interval time.Duration
+}
+
+// The predefined spinners. Each one is also registered under its name in
+// lowercase, e.g. "dots" for SpinnerDots, and the number of its
+// OptionSpinnerType, e.g. "14".
+var (
+	SpinnerArrows         = Spinner{Frames: spinners[0], Interval: 100 * time.Millisecond}
+	SpinnerGrowVertical   = Spinner{Frames: spinners[1], Interval: 80 * time.Millisecond}
+	SpinnerQuadrants      = Spinner{Frames: spinners[2], Interval: 120 * time.Millisecond}
+	SpinnerBoxCorners     = Spinner{Frames: spinners[3], Interval: 100 * time.Millisecond}
+	SpinnerTriangles      = Spinner{Frames: spinners[4], Interval: 50 * time.Millisecond}
+	SpinnerSquareCorners  = Spinner{Frames: spinners[5], Interval: 180 * time.Millisecond}
+	SpinnerCircleQuarters = Spinner{Frames: spinners[6], Interval: 120 * time.Millisecond}
+	SpinnerCircleHalves   = Spinner{Frames: spinners[7], Interval: 50 * time.Millisecond}
+	SpinnerBubbles        = Spinner{Frames: spinners[8], Interval: 120 * time.Millisecond}
+	SpinnerLine           = Spinner{Frames: spinners[9], Interval: 100 * time.Millisecond}
+	SpinnerDots2          = Spinner{Frames: spinners[11], Interval: 80 * time.Millisecond}
+	SpinnerFish           = Spinner{Frames: spinners[12], Interval: 120 * time.Millisecond}
+	SpinnerDots           = Spinner{Frames: spinners[14], Interval: 80 * time.Millisecond}
+	SpinnerGrowHorizontal = Spinner{Frames: spinners[16], Interval: 120 * time.Millisecond}
+	SpinnerEllipsis       = Spinner{Frames: spinners[26], Interval: 300 * time.Millisecond}
+	SpinnerMoon           = Spinner{Frames: spinners[70], Interval: 80 * time.Millisecond}
+)
+
+var (
+	spinnersLock  sync.RWMutex
+	namedSpinners = func() map[string]Spinner {
+		named := map[string]Spinner{
+			"arrows":         SpinnerArrows,
+			"growvertical":   SpinnerGrowVertical,
+			"quadrants":      SpinnerQuadrants,
+			"boxcorners":     SpinnerBoxCorners,
+			"triangles":      SpinnerTriangles,
+			"squarecorners":  SpinnerSquareCorners,
+			"circlequarters": SpinnerCircleQuarters,
+			"circlehalves":   SpinnerCircleHalves,
+			"bubbles":        SpinnerBubbles,
+			"line":           SpinnerLine,
+			"dots2":          SpinnerDots2,
+			"fish":           SpinnerFish,
+			"dots":           SpinnerDots,
+			"growhorizontal": SpinnerGrowHorizontal,
+			"ellipsis":       SpinnerEllipsis,
+			"moon":           SpinnerMoon,
+		}
+		for i, frames := range spinners {
+			named[strconv.Itoa(i)] = Spinner{Frames: frames}
+		}
+		return named
+	}()
+)
+
+// RegisterSpinner makes the spinner available to SpinnerByName and
+// OptionSpinnerByName under name, replacing any spinner already registered
+// under that name. It panics if the spinner has no frames.
+func RegisterSpinner(name string, spinner Spinner) {
+	if len(spinner.Frames) == 0 {
+		panic("invalid spinner, must have at least one frame")
+	}
+	spinnersLock.Lock()
+	defer spinnersLock.Unlock()
+
+	namedSpinners[name] = spinner
+}
+
+// SpinnerByName returns the spinner registered under name, and whether
+// there is one.
+func SpinnerByName(name string) (Spinner, bool) {
+	spinnersLock.RLock()
+	defer spinnersLock.RUnlock()
+
+	spinner, ok := namedSpinners[name]
+	return spinner, ok
+}
+
+// SpinnerNames returns the names of the registered spinners, sorted.
+func SpinnerNames() []string {
+	spinnersLock.RLock()
+	defer spinnersLock.RUnlock()
+
+	names := make([]string, 0, len(namedSpinners))
+	for name := range namedSpinners {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// spinners are the spinners of OptionSpinnerType, by number
 var spinners = map[int][]string{
 	0:  {"←", "↖", "↑", "↗", "→", "↘", "↓", "↙"},
 	1:  {"▁", "▃", "▄", "▅", "▆", "▇", "█", "▇", "▆", "▅", "▄", "▃", "▁"},
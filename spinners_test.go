@@ -0,0 +1,85 @@
+package progressbar
+
+import (
+	"io"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSpinnerByName(t *testing.T) {
+	spinner, ok := SpinnerByName("dots")
+	assert.True(t, ok)
+	assert.Equal(t, SpinnerDots, spinner)
+
+	// the numbers of OptionSpinnerType are registered too
+	spinner, ok = SpinnerByName("14")
+	assert.True(t, ok)
+	assert.Equal(t, SpinnerDots.Frames, spinner.Frames)
+
+	_, ok = SpinnerByName("unknown")
+	assert.False(t, ok)
+
+	RegisterSpinner("test-custom", Spinner{Frames: []string{"a", "b"}})
+	defer func() {
+		spinnersLock.Lock()
+		delete(namedSpinners, "test-custom")
+		spinnersLock.Unlock()
+	}()
+	assert.Contains(t, SpinnerNames(), "test-custom")
+	assert.Contains(t, SpinnerNames(), "75")
+	assert.Panics(t, func() { RegisterSpinner("empty", Spinner{}) })
+}
+
+func TestOptionSpinner(t *testing.T) {
+	bar := NewOptions(-1,
+		OptionSetWriter(io.Discard),
+		OptionSpinner(Spinner{Frames: []string{"a", "b"}}),
+		OptionSetSpinnerChangeInterval(0),
+	)
+	bar.Add(1)
+	assert.Equal(t, "\ra   [0s] ", bar.String())
+	bar.Add(1)
+	assert.Equal(t, "\rb   [0s] ", bar.String())
+}
+
+func TestOptionSpinnerInterval(t *testing.T) {
+	bar := NewOptions(-1, OptionSetWriter(io.Discard), OptionSpinner(SpinnerDots))
+	assert.Equal(t, 80*time.Millisecond, bar.config.spinnerChangeInterval)
+
+	// an explicit interval wins, regardless of the order of the options
+	bar = NewOptions(-1,
+		OptionSetWriter(io.Discard),
+		OptionSetSpinnerChangeInterval(time.Second),
+		OptionSpinnerByName("dots"),
+	)
+	assert.Equal(t, time.Second, bar.config.spinnerChangeInterval)
+	bar.Finish()
+}
+
+func TestOptionSpinnerWithSpinnerType(t *testing.T) {
+	// unlike OptionSpinnerCustom, OptionSpinner combines with OptionSpinnerType
+	bar := NewOptions(-1,
+		OptionSetWriter(io.Discard),
+		OptionSetSpinnerChangeInterval(0),
+		OptionSpinner(SpinnerDots),
+		OptionSpinnerType(9),
+	)
+	assert.NoError(t, bar.Add(1))
+	assert.Equal(t, "\r|   [0s] ", bar.String())
+
+	bar = NewOptions(-1,
+		OptionSetWriter(io.Discard),
+		OptionSetSpinnerChangeInterval(0),
+		OptionSpinnerType(9),
+		OptionSpinner(SpinnerDots),
+	)
+	assert.NoError(t, bar.Add(1))
+	assert.Equal(t, "\r⠋   [0s] ", bar.String())
+}
+
+func TestOptionSpinnerByNameUnknown(t *testing.T) {
+	assert.Panics(t, func() { OptionSpinnerByName("unknown") })
+	assert.Panics(t, func() { OptionSpinner(Spinner{}) })
+}
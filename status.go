@@ -0,0 +1,65 @@
+package progressbar
+
+import (
+	_ "embed"
+	"encoding/json"
+	"net/http"
+)
+
+// statusPage is the page served at / by StartHTTPServer. It has no external
+// dependencies, so it works offline.
+//
+//go:embed assets/status.html
+var statusPage []byte
+
+// status is the JSON served at /status, with everything the status page shows
+type status struct {
+	State
+
+	// Rate is the rolling average rate, in units per second
+	Rate float64
+	// ETA is the predicted number of seconds left, based on Rate
+	ETA      float64
+	Finished bool
+	Exited   bool
+	Paused   bool
+	Details  []string
+	// Bytes is true if the bar counts bytes, see OptionShowBytes
+	Bytes bool
+	// Unit is the name of the iterations, see OptionSetItsString
+	Unit string
+}
+
+// currentStatus returns the status of the bar. this function is not
+// thread-safe, so it must be called with an acquired lock.
+func (p *ProgressBar) currentStatus() status {
+	f := p.frame()
+	return status{
+		State:    finiteState(f.State),
+		Rate:     finite(f.Rate),
+		ETA:      f.ETA.Seconds(),
+		Finished: f.Finished,
+		Exited:   f.Exited,
+		Paused:   f.Paused,
+		Details:  f.Details,
+		Bytes:    p.config.showBytes,
+		Unit:     p.config.iterationString,
+	}
+}
+
+// serveStatus serves the status of the bar as JSON
+func (p *ProgressBar) serveStatus(w http.ResponseWriter, r *http.Request) {
+	p.lock.Lock()
+	s := p.currentStatus()
+	p.lock.Unlock()
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(s)
+}
+
+// serveStatusPage serves the status page, which updates itself from /status
+// whenever /events reports a render
+func serveStatusPage(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	w.Write(statusPage)
+}
@@ -0,0 +1,45 @@
+package progressbar
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestStartHTTPServerStatusPage(t *testing.T) {
+	bar := NewOptions(10, OptionSetWriter(io.Discard), OptionSetDescription("job"), OptionSetMaxDetailRow(1))
+	bar.Add(4)
+	bar.AddDetail("step 2")
+	hostPort := freeTestHTTPAddr(t)
+	svr := bar.StartHTTPServer(hostPort)
+	defer svr.Close()
+
+	resp := getHTTPWithRetry(t, fmt.Sprintf("http://%s/", hostPort))
+	page, _ := io.ReadAll(resp.Body)
+	resp.Body.Close()
+	assert.Equal(t, "text/html; charset=utf-8", resp.Header.Get("Content-Type"))
+	assert.Contains(t, string(page), `new EventSource("events")`)
+	assert.NotContains(t, string(page), "http", "expected no external assets")
+
+	resp = getHTTPWithRetry(t, fmt.Sprintf("http://%s/status", hostPort))
+	var s status
+	assert.NoError(t, json.NewDecoder(resp.Body).Decode(&s))
+	resp.Body.Close()
+	assert.Equal(t, "job", s.Description)
+	assert.Equal(t, int64(4), s.CurrentNum)
+	assert.Equal(t, []string{"step 2"}, s.Details)
+	assert.Equal(t, "it", s.Unit)
+
+	resp = getHTTPWithRetry(t, fmt.Sprintf("http://%s/unknown", hostPort))
+	resp.Body.Close()
+	assert.Equal(t, http.StatusNotFound, resp.StatusCode)
+}
+
+func TestStatusPageEmbedded(t *testing.T) {
+	assert.True(t, strings.HasPrefix(string(statusPage), "<!DOCTYPE html>"))
+}
@@ -0,0 +1,108 @@
+package progressbar
+
+import (
+	"strings"
+	"text/template"
+
+	"github.com/rivo/uniseg"
+)
+
+// TemplateData holds the fields available to the template set with
+// OptionTemplate. The fields are formatted the same way as in the default
+// layout, regardless of the options that show or hide them there.
+type TemplateData struct {
+	// Description is the description of the bar
+	Description string
+	// Bar is the bar itself, including BarStart and BarEnd,
+	// or the spinner if the length is unknown
+	Bar string
+	// Spinner is the spinner, only set if the length is unknown
+	Spinner string
+	// Percent is the current percentage, e.g. "42%"
+	Percent string
+	// Count is the count in "current/total" format, e.g. "42/100" or "4.2 MB/10 MB"
+	Count string
+	// Rate is the rate in bytes per second if OptionShowBytes is set,
+	// otherwise in iterations per second
+	Rate string
+	// Stats is the count and rates in parentheses, as shown by the default layout
+	Stats string
+	// Elapsed is the elapsed time
+	Elapsed string
+	// ETA is the predicted time left
+	ETA string
+
+	// Current is the current number
+	Current int64
+	// Max is the max of the bar, or -1 if the length is unknown
+	Max int64
+	// Finished is true once the bar is finished
+	Finished bool
+	// Exited is true once the bar has exited halfway, see Exit
+	Exited bool
+	// Paused is true while the bar is paused, see Pause
+	Paused bool
+}
+
+// templateFuncs are the functions available to the template set with
+// OptionTemplate, to control the width, padding and color of each field.
+var templateFuncs = template.FuncMap{
+	// left pads s with spaces on the right to the given width, e.g. {{left 20 .Description}}
+	"left": func(width int, s string) string {
+		return s + padding(width, s)
+	},
+	// right pads s with spaces on the left to the given width, e.g. {{right 4 .Percent}}
+	"right": func(width int, s string) string {
+		return padding(width, s) + s
+	},
+	// center pads s with spaces on both sides to the given width
+	"center": func(width int, s string) string {
+		pad := padding(width, s)
+		return pad[:len(pad)/2] + s + pad[len(pad)/2:]
+	},
+	// truncate cuts s to at most the given width
+	"truncate": func(width int, s string) string {
+		var b strings.Builder
+		w := 0
+		g := uniseg.NewGraphemes(s)
+		for g.Next() {
+			w += g.Width()
+			if w > width {
+				break
+			}
+			b.WriteString(g.Str())
+		}
+		return b.String()
+	},
+	// color wraps s in color codes, e.g. {{color "green" .Rate}}.
+	// It requires OptionEnableColorCodes.
+	"color": func(color, s string) string {
+		return "[" + color + "]" + s + "[reset]"
+	},
+}
+
+// padding returns the spaces needed to pad s to the given width
+func padding(width int, s string) string {
+	n := width - uniseg.StringWidth(s)
+	if n <= 0 {
+		return ""
+	}
+	return strings.Repeat(" ", n)
+}
+
+// OptionTemplate sets the layout of the line using text/template, for example
+//
+//	"{{.Description}} {{.Bar}} {{right 4 .Percent}} | {{.Rate}} | ETA {{.ETA}}"
+//
+// See TemplateData for the available fields. The functions left, right,
+// center and truncate take a width and a field, and color takes a color code
+// and a field. With OptionFullWidth, the bar takes the width left over by
+// the rest of the line.
+//
+// It panics if the template cannot be parsed.
+func OptionTemplate(tmpl string) Option {
+	t := template.Must(template.New("progressbar").Funcs(templateFuncs).Parse(tmpl))
+	return func(p *ProgressBar) {
+		p.config.template = t
+	}
+}
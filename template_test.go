@@ -0,0 +1,66 @@
+package progressbar
+
+import (
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestOptionTemplate(t *testing.T) {
+	buf := strings.Builder{}
+	bar := NewOptions(100,
+		OptionSetWriter(&buf),
+		OptionSetWidth(10),
+		OptionSetDescription("copying"),
+		OptionTemplate("{{.Description}} {{.Bar}} {{right 4 .Percent}} | {{.Count}} | ETA {{.ETA}}"),
+	)
+	bar.Add(50)
+	assert.Equal(t, "\rcopying |█████     |  50% | 50/100 | ETA 0s", bar.String())
+
+	bar.Add(50)
+	assert.Equal(t, "\rcopying |██████████| 100% | 100/100 | ETA 0s", bar.String())
+}
+
+func TestOptionTemplateSpinner(t *testing.T) {
+	bar := NewOptions(-1,
+		OptionSetWriter(io.Discard),
+		OptionSpinnerCustom([]string{"*"}),
+		OptionTemplate("{{.Bar}} {{.Description}} {{.Count}}"),
+		OptionSetDescription("waiting"),
+	)
+	bar.Add(5)
+	assert.Equal(t, "\r* waiting 5/-", bar.String())
+}
+
+func TestOptionTemplateFullWidth(t *testing.T) {
+	bar := NewOptions(100,
+		OptionSetWriter(io.Discard),
+		OptionFullWidth(),
+		OptionTemplate("{{.Bar}} {{.Percent}}"),
+	)
+	bar.Add(50)
+	// the bar takes the 80 columns left over by the percentage
+	assert.Equal(t, 80, getStringWidth(bar.config, bar.String()))
+}
+
+func TestOptionTemplateFuncs(t *testing.T) {
+	bar := NewOptions(100,
+		OptionSetWriter(io.Discard),
+		OptionSetDescription("description"),
+		OptionEnableColorCodes(true),
+		OptionTemplate("[{{left 6 .Percent}}][{{center 5 .Percent}}][{{truncate 4 .Description}}]{{color \"green\" .Percent}}"),
+	)
+	bar.Add(5)
+	assert.Equal(t, "\r[5%    ][ 5%  ][desc]\x1b[32m5%\x1b[0m\x1b[0m", bar.String())
+}
+
+func TestOptionTemplateInvalid(t *testing.T) {
+	assert.Panics(t, func() {
+		OptionTemplate("{{.Bar")
+	})
+
+	bar := NewOptions(100, OptionSetWriter(io.Discard), OptionTemplate("{{.Unknown}}"))
+	assert.Error(t, bar.Add(5))
+}
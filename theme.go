@@ -0,0 +1,97 @@
+package progressbar
+
+import (
+	"encoding/json"
+	"io"
+	"sort"
+	"sync"
+)
+
+var (
+	// ThemeLine is a predefined Theme that draws a thin line. It looks like "━━━━╸     ".
+	// Configure it with OptionSetTheme(ThemeLine).
+	ThemeLine = Theme{Saucer: "━", SaucerHead: "╸", SaucerPadding: " "}
+
+	// ThemeShade is a predefined Theme with a shaded track. It looks like "▕████░░░░░▏".
+	// Configure it with OptionSetTheme(ThemeShade).
+	ThemeShade = Theme{Saucer: "█", SaucerPadding: "░", BarStart: "▕", BarEnd: "▏"}
+
+	// ThemeDots is a predefined Theme that uses Braille dots. It looks like "⣿⣿⣿⣿⣀⣀⣀⣀".
+	// Configure it with OptionSetTheme(ThemeDots).
+	ThemeDots = Theme{Saucer: "⣿", SaucerPadding: "⣀"}
+
+	// ThemeColor is a predefined Theme with a green saucer. It looks like "[====>    ]".
+	// It requires OptionEnableColorCodes(true).
+	ThemeColor = Theme{
+		Saucer:        "[green]=[reset]",
+		SaucerHead:    "[green]>[reset]",
+		SaucerPadding: " ",
+		BarStart:      "[",
+		BarEnd:        "]",
+	}
+)
+
+var (
+	themesLock sync.RWMutex
+	themes     = map[string]Theme{
+		"default": ThemeDefault,
+		"ascii":   ThemeASCII,
+		"unicode": ThemeUnicode,
+		"smooth":  ThemeSmooth,
+		"line":    ThemeLine,
+		"shade":   ThemeShade,
+		"dots":    ThemeDots,
+		"color":   ThemeColor,
+	}
+)
+
+// RegisterTheme makes the theme available to ThemeByName under name,
+// replacing any theme already registered under that name. The predefined
+// themes are registered under "default", "ascii", "unicode", "smooth",
+// "line", "shade", "dots" and "color".
+func RegisterTheme(name string, theme Theme) {
+	themesLock.Lock()
+	defer themesLock.Unlock()
+
+	themes[name] = theme
+}
+
+// ThemeByName returns the theme registered under name with RegisterTheme,
+// and whether there is one.
+func ThemeByName(name string) (Theme, bool) {
+	themesLock.RLock()
+	defer themesLock.RUnlock()
+
+	theme, ok := themes[name]
+	return theme, ok
+}
+
+// ThemeNames returns the names of the registered themes, sorted.
+func ThemeNames() []string {
+	themesLock.RLock()
+	defer themesLock.RUnlock()
+
+	names := make([]string, 0, len(themes))
+	for name := range themes {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// LoadTheme reads a theme from JSON, whose keys are the fields of Theme, for
+// example
+//
+//	{"Saucer": "[green]=[reset]", "SaucerHead": ">", "SaucerPadding": " ", "BarStart": "[", "BarEnd": "]"}
+//
+// The fields may hold color tags like the ones of Describe, which require
+// OptionEnableColorCodes(true). Unknown keys are an error, to catch typos.
+func LoadTheme(r io.Reader) (Theme, error) {
+	var theme Theme
+	dec := json.NewDecoder(r)
+	dec.DisallowUnknownFields()
+	if err := dec.Decode(&theme); err != nil {
+		return Theme{}, err
+	}
+	return theme, nil
+}
@@ -0,0 +1,73 @@
+package progressbar
+
+import (
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestThemeByName(t *testing.T) {
+	theme, ok := ThemeByName("ascii")
+	assert.True(t, ok)
+	assert.Equal(t, ThemeASCII, theme)
+
+	_, ok = ThemeByName("unknown")
+	assert.False(t, ok)
+
+	custom := Theme{Saucer: "#", SaucerPadding: "-", BarStart: "<", BarEnd: ">"}
+	RegisterTheme("test-custom", custom)
+	defer func() {
+		themesLock.Lock()
+		delete(themes, "test-custom")
+		themesLock.Unlock()
+	}()
+	theme, ok = ThemeByName("test-custom")
+	assert.True(t, ok)
+	assert.Equal(t, custom, theme)
+	assert.Contains(t, ThemeNames(), "test-custom")
+	assert.Contains(t, ThemeNames(), "smooth")
+}
+
+func TestLoadTheme(t *testing.T) {
+	theme, err := LoadTheme(strings.NewReader(`{
+		"Saucer": "[green]=[reset]",
+		"SaucerHead": ">",
+		"AltSaucerHead": "<",
+		"SaucerPadding": " ",
+		"BarStart": "[",
+		"BarStartFilled": "{",
+		"BarEnd": "]",
+		"BarEndFilled": "}",
+		"SmoothFill": false
+	}`))
+	assert.NoError(t, err)
+	assert.Equal(t, Theme{
+		Saucer:         "[green]=[reset]",
+		SaucerHead:     ">",
+		AltSaucerHead:  "<",
+		SaucerPadding:  " ",
+		BarStart:       "[",
+		BarStartFilled: "{",
+		BarEnd:         "]",
+		BarEndFilled:   "}",
+	}, theme)
+
+	bar := NewOptions(10,
+		OptionSetWriter(io.Discard),
+		OptionSetWidth(4),
+		OptionSetPredictTime(false),
+		OptionEnableColorCodes(true),
+		OptionSetTheme(theme),
+	)
+	bar.Add(5)
+	assert.Equal(t, "\r  50% {\x1b[32m=\x1b[0m>  ]  \x1b[0m", bar.String())
+}
+
+func TestLoadThemeInvalid(t *testing.T) {
+	_, err := LoadTheme(strings.NewReader(`{"Sauser": "="}`))
+	assert.Error(t, err)
+	_, err = LoadTheme(strings.NewReader(`{`))
+	assert.Error(t, err)
+}
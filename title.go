@@ -0,0 +1,112 @@
+package progressbar
+
+import (
+	"fmt"
+	"io"
+	"strings"
+	"text/template"
+	"time"
+	"unicode"
+)
+
+// the xterm sequences that save and restore the window title
+const (
+	pushTitle = "\033[22;2t"
+	popTitle  = "\033[23;2t"
+)
+
+// OptionWindowTitle mirrors the progress into the window title of the
+// terminal with the OSC 2 sequence, for example
+//
+//	"{{.Percent}} {{.Description}} ETA {{.ETA}}"
+//
+// The template gets the same TemplateData and functions as OptionTemplate,
+// except Bar, Spinner and Stats which are empty. The title is updated at
+// most once per OptionThrottle, and the previous title is restored on
+// Finish, Exit and Clear, using the title stack of xterm. It only works with
+// the terminal renderer.
+//
+// It panics if the template cannot be parsed.
+func OptionWindowTitle(tmpl string) Option {
+	t := template.Must(template.New("title").Funcs(templateFuncs).Parse(tmpl))
+	return func(p *ProgressBar) {
+		p.config.windowTitle = t
+	}
+}
+
+// writeWindowTitle writes the window title for the frame, saving the
+// previous title first, or restores the previous title once the bar is
+// done. this function is not thread-safe, so it must be called with an
+// acquired lock.
+func (p *ProgressBar) writeWindowTitle(f Frame) error {
+	if p.config.windowTitle == nil {
+		return nil
+	}
+	if f.Finished || f.Exited {
+		return p.restoreWindowTitle()
+	}
+	if time.Since(p.state.lastTitleTime) < p.config.throttleDuration {
+		return nil
+	}
+
+	var sb strings.Builder
+	if err := p.config.windowTitle.Execute(&sb, p.titleData(f)); err != nil {
+		return err
+	}
+	// control characters would end the sequence early
+	title := strings.Map(func(r rune) rune {
+		if unicode.IsControl(r) {
+			return -1
+		}
+		return r
+	}, sb.String())
+	if p.state.titlePushed && title == p.state.lastTitle {
+		return nil
+	}
+
+	seq := fmt.Sprintf("\033]2;%s\a", title)
+	if !p.state.titlePushed {
+		seq = pushTitle + seq
+		p.state.titlePushed = true
+	}
+	p.state.lastTitle = title
+	p.state.lastTitleTime = time.Now()
+	_, err := io.WriteString(p.config.writer, seq)
+	return err
+}
+
+// restoreWindowTitle restores the title saved by writeWindowTitle, if any.
+// this function is not thread-safe, so it must be called with an acquired
+// lock.
+func (p *ProgressBar) restoreWindowTitle() error {
+	if !p.state.titlePushed {
+		return nil
+	}
+	p.state.titlePushed = false
+	p.state.lastTitle = ""
+	_, err := io.WriteString(p.config.writer, popTitle)
+	return err
+}
+
+// titleData returns the fields of the window title. this function is not
+// thread-safe, so it must be called with an acquired lock.
+func (p *ProgressBar) titleData(f Frame) TemplateData {
+	c := p.config
+	data := TemplateData{
+		Description: c.description,
+		Percent:     fmt.Sprintf("%d%%", p.state.currentPercent),
+		Count:       renderCount(c, &p.state),
+		Rate:        renderItsRate(c, f.Rate),
+		Elapsed:     time.Duration(f.SecondsSince * float64(time.Second)).Truncate(time.Second).String(),
+		ETA:         f.ETA.Truncate(time.Second).String(),
+		Current:     f.CurrentNum,
+		Max:         f.Max,
+		Finished:    f.Finished,
+		Exited:      f.Exited,
+		Paused:      f.Paused,
+	}
+	if c.showBytes {
+		data.Rate = renderBytesRate(c, f.Rate)
+	}
+	return data
+}
@@ -0,0 +1,89 @@
+package progressbar
+
+import (
+	"io"
+	"regexp"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// titleSequences returns the window title sequences written to out
+func titleSequences(out string) []string {
+	return regexp.MustCompile("\033\\[2[23];2t|\033\\]2;[^\a]*\a").FindAllString(out, -1)
+}
+
+func TestOptionWindowTitle(t *testing.T) {
+	buf := strings.Builder{}
+	bar := NewOptions(100,
+		OptionSetWriter(&buf),
+		OptionSetDescription("build"),
+		OptionWindowTitle("{{.Percent}} {{.Description}}{{if .Paused}} (paused){{end}}"),
+	)
+	bar.Add(10)
+	bar.Add(0)
+	bar.Pause()
+	bar.Resume()
+	bar.Finish()
+	assert.Equal(t, []string{
+		pushTitle,
+		"\033]2;10% build\a",
+		"\033]2;10% build (paused)\a",
+		"\033]2;10% build\a",
+		popTitle,
+	}, titleSequences(buf.String()))
+}
+
+func TestOptionWindowTitleThrottle(t *testing.T) {
+	buf := strings.Builder{}
+	bar := NewOptions(100,
+		OptionSetWriter(&buf),
+		OptionThrottle(time.Hour),
+		OptionWindowTitle("{{.Percent}}"),
+	)
+	bar.Add(10)
+	bar.lock.Lock()
+	bar.state.lastShown = time.Time{}
+	bar.lock.Unlock()
+	bar.Add(10)
+	bar.Exit()
+	assert.Equal(t, []string{pushTitle, "\033]2;10%\a", popTitle}, titleSequences(buf.String()))
+}
+
+func TestOptionWindowTitleClear(t *testing.T) {
+	buf := strings.Builder{}
+	bar := NewOptions(100, OptionSetWriter(&buf), OptionWindowTitle("{{.Description}}"))
+	bar.Describe("a\tb\n")
+	bar.Add(10)
+	bar.Clear()
+	bar.Clear()
+	assert.Equal(t, []string{pushTitle, "\033]2;ab\a", popTitle}, titleSequences(buf.String()))
+}
+
+func TestOptionWindowTitleClearConcurrent(t *testing.T) {
+	bar := NewOptions(100, OptionSetWriter(io.Discard), OptionThrottle(0), OptionWindowTitle("{{.Percent}}"))
+	var wg sync.WaitGroup
+	wg.Add(2)
+	go func() {
+		defer wg.Done()
+		for i := 0; i < 50; i++ {
+			bar.Add(1)
+		}
+	}()
+	go func() {
+		defer wg.Done()
+		for i := 0; i < 50; i++ {
+			bar.Clear()
+		}
+	}()
+	wg.Wait()
+}
+
+func TestOptionWindowTitleInvalid(t *testing.T) {
+	assert.Panics(t, func() {
+		OptionWindowTitle("{{.Percent")
+	})
+}